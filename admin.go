@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adminCommand turns the query of an admin request into the method and
+// params of a Claymore control command, and a description for the log. The
+// query is used instead of the form, which would consume the body of file
+// uploads sent as application/x-www-form-urlencoded, as curl does.
+type adminCommand func(r *http.Request) (string, []interface{}, string, error)
+
+// adminFiles are the files that may be pushed to miners.
+var adminFiles = map[string]bool{
+	"config.txt": true,
+	"epools.txt": true,
+	"dpools.txt": true,
+}
+
+// adminFileLimit bounds the size of pushed files.
+const adminFileLimit = 64 * 1024
+
+var adminCommands = map[string]adminCommand{
+	"restart": func(r *http.Request) (string, []interface{}, string, error) {
+		return "miner_restart", nil, "miner_restart", nil
+	},
+	"reboot": func(r *http.Request) (string, []interface{}, string, error) {
+		return "miner_reboot", nil, "miner_reboot", nil
+	},
+	// gpu sets the state of a GPU, or of all GPUs for -1: 0 disables it,
+	// 1 enables it and 2 mines ETH only.
+	"gpu": func(r *http.Request) (string, []interface{}, string, error) {
+		query := r.URL.Query()
+		gpu, err := strconv.Atoi(query.Get("gpu"))
+		if err != nil || gpu < -1 {
+			return "", nil, "", fmt.Errorf("invalid gpu %q", query.Get("gpu"))
+		}
+		state, err := strconv.Atoi(query.Get("state"))
+		if err != nil || state < 0 || state > 2 {
+			return "", nil, "", fmt.Errorf("invalid state %q", query.Get("state"))
+		}
+		return "control_gpu", []interface{}{gpu, state}, fmt.Sprintf("control_gpu %d %d", gpu, state), nil
+	},
+	// file replaces a configuration file with the request body.
+	"file": func(r *http.Request) (string, []interface{}, string, error) {
+		name := r.URL.Query().Get("name")
+		if !adminFiles[name] {
+			return "", nil, "", fmt.Errorf("invalid file %q", name)
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(r.Body, adminFileLimit+1))
+		if err != nil {
+			return "", nil, "", err
+		}
+		if len(content) == 0 || len(content) > adminFileLimit {
+			return "", nil, "", fmt.Errorf("file must have between 1 and %d bytes", adminFileLimit)
+		}
+		sum := sha256.Sum256(content)
+		return "miner_file", []interface{}{name, hex.EncodeToString(content)}, fmt.Sprintf("miner_file %s sha256 %x", name, sum), nil
+	},
+}
+
+// NewAdminHandler forwards control commands to the Claymore protocol miners
+// among the exporters, addressed by a comma separated list of target names:
+//
+//	POST /admin/restart?target=rig1
+//	POST /admin/reboot?target=rig1
+//	POST /admin/gpu?target=rig1&gpu=2&state=0
+//	POST /admin/file?target=rig1,rig2&name=epools.txt (with the file as body)
+//
+// Requests must carry the token as bearer token. Miners without an API
+// password are refused, as anyone on the network could control them anyway.
+// Every request is written to the log.
+func NewAdminHandler(exporters []*Exporter, token string, timeout time.Duration) http.Handler {
+	targets := map[string]*ClaymoreClient{}
+	for _, e := range exporters {
+		if miner, ok := e.miner.(*ClaymoreClient); ok {
+			targets[e.name] = miner
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		action := strings.TrimPrefix(r.URL.Path, "/admin/")
+		names := strings.Split(r.URL.Query().Get("target"), ",")
+
+		status, message := admin(r, targets, token, action, names, timeout)
+		log.Printf("Admin %s on %q from %s: %d %s", action, names, r.RemoteAddr, status, message)
+
+		http.Error(w, message, status)
+	})
+}
+
+func admin(r *http.Request, targets map[string]*ClaymoreClient, token, action string, names []string, timeout time.Duration) (int, string) {
+	if r.Method != "POST" {
+		return http.StatusMethodNotAllowed, "Admin commands must be POSTed"
+	}
+
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		return http.StatusUnauthorized, "Invalid admin token"
+	}
+
+	command, ok := adminCommands[action]
+	if !ok {
+		return http.StatusNotFound, fmt.Sprintf("Unknown admin command %q", action)
+	}
+
+	// All targets are checked before any of them is sent the command.
+	miners := []*ClaymoreClient{}
+	for _, name := range names {
+		miner, ok := targets[name]
+		if !ok {
+			return http.StatusNotFound, fmt.Sprintf("Unknown Claymore target %q", name)
+		}
+		if miner.password == "" {
+			return http.StatusForbidden, fmt.Sprintf("Target %q has no API password", name)
+		}
+		miners = append(miners, miner)
+	}
+
+	method, params, description, err := command(r)
+	if err != nil {
+		return http.StatusBadRequest, err.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	status := http.StatusOK
+	results := []string{}
+	for i, miner := range miners {
+		if err := miner.Control(ctx, method, params...); err != nil {
+			status = http.StatusBadGateway
+			results = append(results, fmt.Sprintf("%s: sending %s failed: %s", names[i], description, err))
+			continue
+		}
+		results = append(results, fmt.Sprintf("%s: sent %s", names[i], description))
+	}
+
+	return status, strings.Join(results, "; ")
+}
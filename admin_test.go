@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func adminRequest(handler http.Handler, method, url, token string) *httptest.ResponseRecorder {
+	return adminUpload(handler, method, url, token, "", "")
+}
+
+func adminUpload(handler http.Handler, method, url, token, contentType, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestAdminControlGPU(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	requests := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		request, _ := bufio.NewReader(conn).ReadString('\n')
+		requests <- request
+	}()
+
+	miner, _ := NewClaymoreClient(TargetConfig{Address: listener.Addr().String(), Password: "secret"})
+	handler := NewAdminHandler([]*Exporter{NewExporter(miner, "rig1", nil)}, "admin", time.Second)
+
+	w := adminRequest(handler, "POST", "/admin/gpu?target=rig1&gpu=2&state=0", "admin")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"id":0,"jsonrpc":"2.0","method":"control_gpu","params":[2,0],"psw":"secret"}`+"\n", <-requests)
+}
+
+func TestAdminPushFile(t *testing.T) {
+	requests := make(chan string, 2)
+	exporters := []*Exporter{}
+	for _, name := range []string{"rig1", "rig2"} {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+		defer listener.Close()
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			request, _ := bufio.NewReader(conn).ReadString('\n')
+			requests <- request
+		}()
+
+		miner, _ := NewClaymoreClient(TargetConfig{Address: listener.Addr().String(), Password: "secret"})
+		exporters = append(exporters, NewExporter(miner, name, nil))
+	}
+	handler := NewAdminHandler(exporters, "admin", time.Second)
+
+	// curl --data-binary sends files as a form unless told otherwise.
+	w := adminUpload(handler, "POST", "/admin/file?target=rig1,rig2&name=epools.txt", "admin", "application/x-www-form-urlencoded", "POOL: eu1.ethermine.org:4444")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	for i := 0; i < 2; i++ {
+		assert.Contains(t, <-requests, `"method":"miner_file","params":["epools.txt","504f4f4c3a206575312e65746865726d696e652e6f72673a34343434"]`)
+	}
+
+	w = adminUpload(handler, "POST", "/admin/file?target=rig1&name=../boot.ini", "admin", "", "POOL: eu1.ethermine.org:4444")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminRefused(t *testing.T) {
+	miner, _ := NewClaymoreClient(TargetConfig{Address: "127.0.0.1:3333", Password: "secret"})
+	open, _ := NewClaymoreClient(TargetConfig{Address: "127.0.0.1:3334"})
+	handler := NewAdminHandler([]*Exporter{
+		NewExporter(miner, "rig1", nil),
+		NewExporter(open, "rig2", nil),
+	}, "admin", time.Second)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, adminRequest(handler, "GET", "/admin/restart?target=rig1", "admin").Code)
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(handler, "POST", "/admin/restart?target=rig1", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(handler, "POST", "/admin/restart?target=rig1", "wrong").Code)
+	assert.Equal(t, http.StatusNotFound, adminRequest(handler, "POST", "/admin/shutdown?target=rig1", "admin").Code)
+	assert.Equal(t, http.StatusNotFound, adminRequest(handler, "POST", "/admin/restart?target=rig3", "admin").Code)
+	assert.Equal(t, http.StatusForbidden, adminRequest(handler, "POST", "/admin/restart?target=rig2", "admin").Code)
+	assert.Equal(t, http.StatusBadRequest, adminRequest(handler, "POST", "/admin/gpu?target=rig1&gpu=0&state=3", "admin").Code)
+}
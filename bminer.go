@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+type bminerStatus struct {
+	Version   string `json:"version"`
+	StartTime int64  `json:"start_time"`
+	Stratum   struct {
+		AcceptedShares float64 `json:"accepted_shares"`
+		RejectedShares float64 `json:"rejected_shares"`
+	} `json:"stratum"`
+	Miners map[string]struct {
+		Solver struct {
+			SolutionRate float64 `json:"solution_rate"`
+		} `json:"solver"`
+	} `json:"miners"`
+}
+
+type bminerDevices struct {
+	Devices map[string]struct {
+		Temperature *float64 `json:"temperature"`
+		Power       *float64 `json:"power"`
+		FanSpeed    *float64 `json:"fan_speed"`
+		Clocks      struct {
+			Core   *float64 `json:"core"`
+			Memory *float64 `json:"memory"`
+		} `json:"clocks"`
+	} `json:"devices"`
+}
+
+type bminerStratums struct {
+	Stratums map[string]struct {
+		AcceptedShares float64 `json:"accepted_shares"`
+		RejectedShares float64 `json:"rejected_shares"`
+	} `json:"stratums"`
+}
+
+// NewBminerClient reads the status, devices and stratums of Bminer. Its
+// status reports a single solution rate per device without naming the
+// algorithm, so the rates go to the given algorithm, or to the only stratum
+// if there is just one.
+func NewBminerClient(address string, algorithm string) *HTTPMinerClient {
+	return newHTTPMinerClient(address, "bminer", func(ctx context.Context, api HTTPMinerAPI) (*Metrics, error) {
+		return collectBminer(ctx, api, algorithm)
+	})
+}
+
+func collectBminer(ctx context.Context, api HTTPMinerAPI, algorithm string) (*Metrics, error) {
+	status := bminerStatus{}
+	if err := api.Get(ctx, "/api/status", &status); err != nil {
+		return nil, err
+	}
+
+	devices := bminerDevices{}
+	if err := api.Get(ctx, "/api/v1/status/device", &devices); err != nil {
+		return nil, err
+	}
+
+	stratums := bminerStratums{}
+	if err := api.Get(ctx, "/api/v1/status/stratum", &stratums); err != nil {
+		return nil, err
+	}
+
+	// Devices are keyed by their index.
+	ids := []string{}
+	for id := range devices.Devices {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return byNumber(ids[i], ids[j]) })
+
+	total := 0.0
+	byGPU := []float64{}
+	gpus := []GPU{}
+	for _, id := range ids {
+		device := devices.Devices[id]
+		rate := status.Miners[id].Solver.SolutionRate
+		total = total + rate
+		byGPU = append(byGPU, rate)
+		gpus = append(gpus, GPU{
+			Temperature: device.Temperature,
+			FanPercent:  device.FanSpeed,
+			Power:       device.Power,
+			CoreClock:   device.Clocks.Core,
+			MemoryClock: device.Clocks.Memory,
+		})
+	}
+
+	names := []string{}
+	for name := range stratums.Stratums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	primary := algorithm
+	if primary == "" && len(names) == 1 {
+		primary = names[0]
+	}
+	if primary == "" {
+		primary = "unknown"
+	}
+
+	// The status carries the shares of the primary stratum, which are kept
+	// even while the stratums are unavailable.
+	algorithms := []Algorithm{
+		{
+			Name: primary,
+			Shares: Shares{
+				Accepted: status.Stratum.AcceptedShares,
+				Rejected: status.Stratum.RejectedShares,
+			},
+			Rates: Rates{Total: total, ByGPU: byGPU},
+		},
+	}
+	for _, name := range names {
+		if name == primary {
+			continue
+		}
+		stratum := stratums.Stratums[name]
+		algorithms = append(algorithms, Algorithm{
+			Name: name,
+			Shares: Shares{
+				Accepted: stratum.AcceptedShares,
+				Rejected: stratum.RejectedShares,
+			},
+			// The solution rates all belong to the primary algorithm.
+			Rates: Rates{Unreported: true},
+		})
+	}
+
+	uptime := 0.0
+	if status.StartTime > 0 {
+		uptime = time.Since(time.Unix(status.StartTime, 0)).Seconds()
+	}
+
+	return &Metrics{
+		Version:    status.Version,
+		Uptime:     uptime,
+		Algorithms: algorithms,
+		GPUs:       gpus,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	BMINER_STATUS = `{
+   "stratum":{"accepted_shares":150, "rejected_shares":1, "accepted_share_rate":0.41, "rejected_share_rate":0},
+   "miners":{
+      "0":{"solver":{"solution_rate":455.2, "nonce_rate":245.1}, "device":{"temperature":61, "power":121, "fan_speed":55}},
+      "1":{"solver":{"solution_rate":448.8, "nonce_rate":241.7}, "device":{"temperature":64, "power":119, "fan_speed":58}},
+      "10":{"solver":{"solution_rate":450, "nonce_rate":242.0}, "device":{"temperature":59, "power":120, "fan_speed":50}}
+   },
+   "version":"v16.4.11",
+   "start_time":1615000000
+}`
+
+	BMINER_DEVICES = `{
+   "devices":{
+      "0":{"temperature":61, "power":121, "fan_speed":55, "global_memory_used":1024, "utilization":{"gpu":100, "memory":20}, "clocks":{"core":1873, "memory":4006}, "pci":{"bar1_used":5, "rx_throughput":0, "tx_throughput":0}},
+      "1":{"temperature":64, "power":119, "fan_speed":58, "global_memory_used":1024, "utilization":{"gpu":100, "memory":20}, "clocks":{"core":1860, "memory":4006}, "pci":{"bar1_used":5, "rx_throughput":0, "tx_throughput":0}},
+      "10":{"temperature":59, "power":120, "fan_speed":50, "global_memory_used":1024, "utilization":{"gpu":100, "memory":20}, "clocks":{"core":1848, "memory":4006}, "pci":{"bar1_used":5, "rx_throughput":0, "tx_throughput":0}}
+   }
+}`
+
+	BMINER_STRATUMS = `{
+   "stratums":{
+      "equihash":{"accepted_shares":150, "rejected_shares":1, "accepted_share_rate":0.41, "rejected_share_rate":0}
+   }
+}`
+)
+
+func TestBminerCollect(t *testing.T) {
+	server := fixtureServer(map[string]string{
+		"/api/status":            BMINER_STATUS,
+		"/api/v1/status/device":  BMINER_DEVICES,
+		"/api/v1/status/stratum": BMINER_STRATUMS,
+	})
+	defer server.Close()
+
+	miner := NewBminerClient(server.URL, "")
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "bminer", miner.Name())
+	assert.Equal(t, "v16.4.11", metrics.Version)
+	assert.True(t, metrics.Uptime > 0)
+	assert.Equal(t, 1, len(metrics.Algorithms))
+	assert.Equal(t, "equihash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 150.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 1354.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{455.2, 448.8, 450}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, 3, len(metrics.GPUs))
+	assert.Equal(t, 59.0, *metrics.GPUs[2].Temperature)
+	assert.Equal(t, 50.0, *metrics.GPUs[2].FanPercent)
+	assert.Equal(t, 120.0, *metrics.GPUs[2].Power)
+	assert.Equal(t, 1848.0, *metrics.GPUs[2].CoreClock)
+	assert.Equal(t, 4006.0, *metrics.GPUs[2].MemoryClock)
+}
+
+func TestBminerCollectDual(t *testing.T) {
+	server := fixtureServer(map[string]string{
+		"/api/status":           BMINER_STATUS,
+		"/api/v1/status/device": BMINER_DEVICES,
+		"/api/v1/status/stratum": `{
+   "stratums":{
+      "blake2s":{"accepted_shares":12, "rejected_shares":0},
+      "ethash":{"accepted_shares":150, "rejected_shares":1}
+   }
+}`,
+	})
+	defer server.Close()
+
+	metrics, err := NewBminerClient(server.URL, "ethash").Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(metrics.Algorithms))
+	assert.Equal(t, "ethash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 1354.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, "blake2s", metrics.Algorithms[1].Name)
+	assert.Equal(t, 12.0, metrics.Algorithms[1].Shares.Accepted)
+	assert.True(t, metrics.Algorithms[1].Rates.Unreported)
+	assert.Nil(t, metrics.Algorithms[1].Rates.ByGPU)
+}
+
+func TestBminerCollectWithoutStratums(t *testing.T) {
+	server := fixtureServer(map[string]string{
+		"/api/status":            BMINER_STATUS,
+		"/api/v1/status/device":  BMINER_DEVICES,
+		"/api/v1/status/stratum": `{"stratums":{}}`,
+	})
+	defer server.Close()
+
+	metrics, err := NewBminerClient(server.URL, "").Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(metrics.Algorithms))
+	assert.Equal(t, "unknown", metrics.Algorithms[0].Name)
+	assert.Equal(t, 1354.0, metrics.Algorithms[0].Rates.Total)
+}
@@ -2,19 +2,30 @@ package main
 
 import (
 	"bufio"
-	"net"
+	"context"
+	"io"
+	"log"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type CCMinerAPI interface {
-	Summary() (string, error)
-	Threads() (string, error)
-	Pool() (string, error)
+	Summary(ctx context.Context) (string, error)
+	Threads(ctx context.Context) (string, error)
+	Pool(ctx context.Context) (string, error)
+	HWInfo(ctx context.Context) (string, error)
+	MemInfo(ctx context.Context) (string, error)
+	Histo(ctx context.Context) (string, error)
 }
 
 type CCMinerClient struct {
 	api CCMinerAPI
+
+	// failing holds the optional commands whose last read failed, so that
+	// builds without them log that only once.
+	mu      sync.Mutex
+	failing map[string]bool
 }
 
 type client struct {
@@ -22,48 +33,93 @@ type client struct {
 }
 
 func NewCCMinerClient(address string) *CCMinerClient {
-	return &CCMinerClient{&client{address}}
+	return &CCMinerClient{api: &client{address}}
 }
 
 func (c *CCMinerClient) Name() string {
 	return "ccminer"
 }
 
-func (c *CCMinerClient) Collect() (*Metrics, error) {
-	resp, err := c.api.Summary()
+func (c *CCMinerClient) Collect(ctx context.Context) (*Metrics, error) {
+	resp, err := c.api.Summary(ctx)
 	if err != nil {
 		return nil, err
 	}
 	summary := toMap(resp)
 
-	resp, err = c.api.Pool()
+	resp, err = c.api.Pool(ctx)
 	if err != nil {
 		return nil, err
 	}
 	pool := toMap(resp)
 
-	resp, err = c.api.Threads()
+	resp, err = c.api.Threads(ctx)
 	if err != nil {
 		return nil, err
 	}
 	threads := toMaps(resp)
 
+	// hwinfo, meminfo and histo are extras of tpruvot's ccminer that other
+	// builds lack, so they only add to the metrics if they can be read.
+	hwinfo, driver := hardware(toMaps(c.optional(ctx, "hwinfo", c.api.HWInfo)))
+	meminfo := toMap(strings.TrimSuffix(c.optional(ctx, "meminfo", c.api.MemInfo), "|"))
+	history := smoothed(toMaps(c.optional(ctx, "histo", c.api.Histo)))
+
 	uptime, _ := strconv.ParseFloat(summary["UPTIME"], 64)
 	accepted, _ := strconv.ParseFloat(pool["ACC"], 64)
 	rejected, _ := strconv.ParseFloat(pool["REJ"], 64)
 	stale, _ := strconv.ParseFloat(pool["STALE"], 64)
 
 	byGPU := []float64{}
+	average := []*float64{}
+	efficiency := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
 	total := 0.0
 	for _, gpu := range threads {
 		rate, _ := strconv.ParseFloat(gpu["KHS"], 64)
 		byGPU = append(byGPU, rate)
 		total = total + rate
+		// GPUs without recent scans are left out rather than reported idle.
+		if rate, ok := history[gpu["GPU"]]; ok {
+			average = append(average, reading(rate))
+		} else {
+			average = append(average, nil)
+		}
+
+		khw, _ := strconv.ParseFloat(gpu["KHW"], 64)
+		efficiency = append(efficiency, khw)
+
+		acc, _ := strconv.ParseFloat(gpu["ACC"], 64)
+		rej, _ := strconv.ParseFloat(gpu["REJ"], 64)
+		sharesByGPU = append(sharesByGPU, Shares{
+			Accepted: acc,
+			Rejected: rej,
+		})
+
+		hw := hwinfo[gpu["GPU"]]
+		gpus = append(gpus, GPU{
+			Card:        gpu["CARD"],
+			BIOS:        hw["BIOS"],
+			Driver:      driver,
+			Vendor:      hw["VID"],
+			Bus:         hw["BUS"],
+			Temperature: parseReading(gpu["TEMP"]),
+			FanPercent:  parseReading(gpu["FAN"]),
+			FanRPM:      parseReading(gpu["RPM"]),
+			// ccminer reports the power draw in milliwatts.
+			Power:          milli(parseReading(gpu["POWER"])),
+			PowerLimit:     parseReading(gpu["PLIM"]),
+			CoreClock:      parseReading(gpu["FREQ"]),
+			MemoryClock:    parseReading(gpu["MEMFREQ"]),
+			HardwareErrors: parseReading(gpu["HWF"]),
+		})
 	}
 
 	return &Metrics{
 		Version: summary["VER"],
 		Uptime:  uptime,
+		Memory:  parseReading(meminfo["MEM"]),
 		Algorithms: []Algorithm{
 			{
 				Name: summary["ALGO"],
@@ -73,19 +129,94 @@ func (c *CCMinerClient) Collect() (*Metrics, error) {
 					Stale:    stale,
 				},
 				Rates: Rates{
-					Total: total,
-					ByGPU: byGPU,
+					Total:           total,
+					ByGPU:           byGPU,
+					AverageByGPU:    average,
+					EfficiencyByGPU: efficiency,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool: Pool{
+					URL:        pool["URL"],
+					User:       pool["USER"],
+					Difficulty: parseReading(pool["DIFF"]),
+					// ccminer reports the ping in milliseconds.
+					Latency:     milli(parseReading(pool["PING"])),
+					Disconnects: parseReading(pool["DISCO"]),
+					LastShare:   parseReading(pool["LAST"]),
+					Connected:   parseReading(pool["UPTIME"]),
 				},
 			},
 		},
+		GPUs: gpus,
 	}, nil
 }
 
-func (c *client) rpc(command string) (string, error) {
-	conn, err := net.Dial("tcp", c.address)
+// optional runs one of the commands not every ccminer answers, returning an
+// empty reply if it fails. A failure is logged once until the command is
+// answered again.
+func (c *CCMinerClient) optional(ctx context.Context, command string, call func(context.Context) (string, error)) string {
+	resp, err := call(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		if !c.failing[command] {
+			log.Printf("Failed to read %s from ccminer, leaving it out: %s\n", command, err)
+		}
+		if c.failing == nil {
+			c.failing = map[string]bool{}
+		}
+		c.failing[command] = true
+		return ""
+	}
+
+	delete(c.failing, command)
+	return resp
+}
+
+// hardware keys the hwinfo entries of the GPUs by their number. The entry
+// without a number describes the system, including the NVIDIA driver.
+func hardware(entries []map[string]string) (map[string]map[string]string, string) {
+	gpus := map[string]map[string]string{}
+	driver := ""
+	for _, entry := range entries {
+		if gpu, ok := entry["GPU"]; ok {
+			gpus[gpu] = entry
+			continue
+		}
+		if entry["NVDRIVER"] != "" {
+			driver = entry["NVDRIVER"]
+		}
+	}
+	return gpus, driver
+}
+
+// smoothed averages the rates of the recent scans in histo by GPU number.
+func smoothed(entries []map[string]string) map[string]float64 {
+	sums := map[string]float64{}
+	counts := map[string]float64{}
+	for _, entry := range entries {
+		rate, err := strconv.ParseFloat(entry["KHS"], 64)
+		if err != nil {
+			continue
+		}
+		sums[entry["GPU"]] += rate
+		counts[entry["GPU"]]++
+	}
+
+	for gpu := range sums {
+		sums[gpu] = sums[gpu] / counts[gpu]
+	}
+	return sums
+}
+
+func (c *client) rpc(ctx context.Context, command string) (string, error) {
+	conn, err := dial(ctx, "tcp", c.address)
 	if err != nil {
 		return "", err
 	}
+	defer conn.Close()
 
 	_, err = conn.Write([]byte(command))
 	if err != nil {
@@ -93,23 +224,40 @@ func (c *client) rpc(command string) (string, error) {
 	}
 
 	scanner := bufio.NewScanner(bufio.NewReader(conn))
-	if scanner.Scan(); scanner.Err() != nil {
-		return "", err
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.ErrUnexpectedEOF
 	}
 
 	return scanner.Text(), nil
 }
 
-func (c *client) Summary() (string, error) {
-	return c.rpc("summary")
+func (c *client) Summary(ctx context.Context) (string, error) {
+	return c.rpc(ctx, "summary")
+}
+
+func (c *client) Threads(ctx context.Context) (string, error) {
+	return c.rpc(ctx, "threads")
+}
+
+func (c *client) Pool(ctx context.Context) (string, error) {
+	return c.rpc(ctx, "pool")
+}
+
+func (c *client) HWInfo(ctx context.Context) (string, error) {
+	return c.rpc(ctx, "hwinfo")
 }
 
-func (c *client) Threads() (string, error) {
-	return c.rpc("threads")
+// MemInfo reports the memory ccminer uses for its stats and hash log.
+func (c *client) MemInfo(ctx context.Context) (string, error) {
+	return c.rpc(ctx, "meminfo")
 }
 
-func (c *client) Pool() (string, error) {
-	return c.rpc("pool")
+// Histo reports the rates of the recent scans of each GPU.
+func (c *client) Histo(ctx context.Context) (string, error) {
+	return c.rpc(ctx, "histo")
 }
 
 func toMaps(input string) []map[string]string {
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,23 +18,44 @@ const (
 	THREADS = "GPU=0;BUS=-1;CARD=GeForce GTX 1070;TEMP=0.0;POWER=0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;KHS=0.31;KHW=0.00000;PLIM=0;ACC=0;REJ=0;HWF=0;I=9.9;THR=960|GPU=1;BUS=-1;CARD=GeForce GTX 1070;TEMP=0.0;POWER=0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;KHS=0.31;KHW=0.00000;PLIM=0;ACC=1;REJ=0;HWF=0;I=9.9;THR=960|GPU=2;BUS=-1;CARD=GeForce GTX 1070;TEMP=0.0;POWER=0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;KHS=0.27;KHW=0.00000;PLIM=0;ACC=0;REJ=0;HWF=0;I=9.9;THR=960|GPU=3;BUS=-1;CARD=GeForce GTX 1070;TEMP=0.0;POWER=0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;KHS=0.30;KHW=0.00000;PLIM=0;ACC=0;REJ=0;HWF=0;I=9.9;THR=960|GPU=4;BUS=-1;CARD=GeForce GTX 1070;TEMP=0.0;POWER=0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;KHS=0.30;KHW=0.00000;PLIM=0;ACC=2;REJ=0;HWF=0;I=9.9;THR=960|GPU=5;BUS=-1;CARD=GeForce GTX 1070;TEMP=0.0;POWER=0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;KHS=0.30;KHW=0.00000;PLIM=0;ACC=0;REJ=0;HWF=0;I=9.9;THR=960"
 
 	POOL = "POOL=europe.cryptonight-hub.miningpoolhub.com:17024;ALGO=cryptonight;URL=stratum+tcp://europe.cryptonight-hub.miningpoolhub.com:17024;USER=bugroger.wupse;SOLV=0;ACC=3;REJ=0;STALE=1;H=0;JOB=;DIFF=84035.439844;BEST=662.573037;N2SZ=0;N2=;PING=412;DISCO=0;WAIT=5;UPTIME=0;LAST=45"
+
+	HWINFO = "GPU=0;BUS=1;CARD=GeForce GTX 1070;SM=601;MEM=8506048512;TEMP=0.0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;PST=P2;POWER=0;VID=10de;PID=1b81;NVML=1;NVAPI=0;SN=0323817003401;BIOS=86.04.50.00.70|GPU=1;BUS=2;CARD=GeForce GTX 1070;SM=601;MEM=8506048512;TEMP=0.0;FAN=0;RPM=0;FREQ=1683;MEMFREQ=4004;GPUF=0;MEMF=0;PST=P2;POWER=0;VID=10de;PID=1b81;NVML=1;NVAPI=0;SN=0323817003402;BIOS=86.04.50.00.70|CPUS=4;CPUFREQ=3400;CPUTEMP=0.0;OS=linux;NVDRIVER=390.25|"
+
+	MEMINFO = "STATS=12;HASHLOG=3;MEM=1536|"
+
+	HISTO = "GPU=0;H=0;KHS=0.30;DIFF=0.003906;COUNT=1472;FOUND=0;ID=1;TS=1518364700|GPU=1;H=0;KHS=0.31;DIFF=0.003906;COUNT=1472;FOUND=0;ID=2;TS=1518364701|GPU=0;H=0;KHS=0.32;DIFF=0.003906;COUNT=1472;FOUND=0;ID=3;TS=1518364730|"
 )
 
 type MockedCCMinerAPI struct {
 	mock.Mock
 }
 
-func (m *MockedCCMinerAPI) Summary() (string, error) {
+func (m *MockedCCMinerAPI) Summary(ctx context.Context) (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockedCCMinerAPI) Threads(ctx context.Context) (string, error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockedCCMinerAPI) Threads() (string, error) {
+func (m *MockedCCMinerAPI) Pool(ctx context.Context) (string, error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockedCCMinerAPI) Pool() (string, error) {
+func (m *MockedCCMinerAPI) HWInfo(ctx context.Context) (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockedCCMinerAPI) MemInfo(ctx context.Context) (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockedCCMinerAPI) Histo(ctx context.Context) (string, error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
@@ -40,9 +66,12 @@ func TestCollect(t *testing.T) {
 	mockAPI.On("Summary").Return(SUMMARY, nil)
 	mockAPI.On("Threads").Return(THREADS, nil)
 	mockAPI.On("Pool").Return(POOL, nil)
+	mockAPI.On("HWInfo").Return(HWINFO, nil)
+	mockAPI.On("MemInfo").Return(MEMINFO, nil)
+	mockAPI.On("Histo").Return(HISTO, nil)
 
-	ccminer := &CCMinerClient{mockAPI}
-	metrics, _ := ccminer.Collect()
+	ccminer := &CCMinerClient{api: mockAPI}
+	metrics, _ := ccminer.Collect(context.Background())
 
 	assert.Equal(t, "ccminer", ccminer.Name())
 	assert.Equal(t, "2.2.4", metrics.Version)
@@ -58,4 +87,58 @@ func TestCollect(t *testing.T) {
 	assert.Equal(t, 0.30, metrics.Algorithms[0].Rates.ByGPU[4])
 	assert.Equal(t, 0.30, metrics.Algorithms[0].Rates.ByGPU[5])
 	assert.Equal(t, 1.79, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, 0.0, metrics.Algorithms[0].Rates.EfficiencyByGPU[0])
+	assert.InDelta(t, 0.31, *metrics.Algorithms[0].Rates.AverageByGPU[0], 1e-9)
+	assert.Equal(t, 0.31, *metrics.Algorithms[0].Rates.AverageByGPU[1])
+	assert.Nil(t, metrics.Algorithms[0].Rates.AverageByGPU[2])
+	assert.Equal(t, 1536.0, *metrics.Memory)
+	assert.Equal(t, 6, len(metrics.GPUs))
+	assert.Equal(t, "GeForce GTX 1070", metrics.GPUs[0].Card)
+	assert.Equal(t, "86.04.50.00.70", metrics.GPUs[0].BIOS)
+	assert.Equal(t, "390.25", metrics.GPUs[0].Driver)
+	assert.Equal(t, "10de", metrics.GPUs[0].Vendor)
+	assert.Equal(t, "2", metrics.GPUs[1].Bus)
+	assert.Equal(t, "", metrics.GPUs[2].BIOS)
+	assert.Equal(t, 0.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 1683.0, *metrics.GPUs[0].CoreClock)
+	assert.Equal(t, 4004.0, *metrics.GPUs[0].MemoryClock)
+	assert.Equal(t, 0.0, *metrics.GPUs[0].Power)
+	assert.Equal(t, 0.0, *metrics.GPUs[0].HardwareErrors)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].SharesByGPU[1].Accepted)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].SharesByGPU[4].Accepted)
+	assert.Equal(t, 0.0, metrics.Algorithms[0].SharesByGPU[4].Rejected)
+	assert.Equal(t, "stratum+tcp://europe.cryptonight-hub.miningpoolhub.com:17024", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "bugroger.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, 84035.439844, *metrics.Algorithms[0].Pool.Difficulty)
+	assert.Equal(t, 0.412, *metrics.Algorithms[0].Pool.Latency)
+	assert.Equal(t, 45.0, *metrics.Algorithms[0].Pool.LastShare)
+}
+
+func TestCollectWithoutExtras(t *testing.T) {
+	mockAPI := new(MockedCCMinerAPI)
+
+	mockAPI.On("Summary").Return(SUMMARY, nil)
+	mockAPI.On("Threads").Return(THREADS, nil)
+	mockAPI.On("Pool").Return(POOL, nil)
+	mockAPI.On("HWInfo").Return("", io.ErrUnexpectedEOF)
+	mockAPI.On("MemInfo").Return("", io.ErrUnexpectedEOF)
+	mockAPI.On("Histo").Return("", io.ErrUnexpectedEOF)
+
+	ccminer := &CCMinerClient{api: mockAPI}
+	metrics, err := ccminer.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Nil(t, metrics.Memory)
+	assert.Equal(t, "", metrics.GPUs[0].BIOS)
+	assert.Nil(t, metrics.Algorithms[0].Rates.AverageByGPU[0])
+	assert.Equal(t, 1.79, metrics.Algorithms[0].Rates.Total)
+	assert.True(t, ccminer.failing["histo"])
+
+	// Builds without the commands log their failure only once.
+	logs := bytes.Buffer{}
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	ccminer.Collect(context.Background())
+	assert.Equal(t, "", logs.String())
 }
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// CGMinerAPI speaks the JSON API of cgminer and its forks, like sgminer,
+// BFGMiner, TeamRedMiner and the firmware of most ASICs.
+type CGMinerAPI interface {
+	Command(ctx context.Context, command string) ([]byte, error)
+}
+
+type CGMinerClient struct {
+	api       CGMinerAPI
+	algorithm string
+
+	// asic additionally reads hashboards and fans from the stats command.
+	asic bool
+}
+
+type cgminerAPIClient struct {
+	address string
+}
+
+type cgminerReply struct {
+	Summary []struct {
+		Status  []cgminerStatus  `json:"STATUS"`
+		Summary []cgminerSummary `json:"SUMMARY"`
+	} `json:"summary"`
+	Devs []struct {
+		Status []cgminerStatus `json:"STATUS"`
+		Devs   []cgminerDevice `json:"DEVS"`
+	} `json:"devs"`
+	Pools []struct {
+		Status []cgminerStatus `json:"STATUS"`
+		Pools  []cgminerPool   `json:"POOLS"`
+	} `json:"pools"`
+	Version []struct {
+		Status  []cgminerStatus          `json:"STATUS"`
+		Version []map[string]interface{} `json:"VERSION"`
+	} `json:"version"`
+	Stats []struct {
+		Status []cgminerStatus          `json:"STATUS"`
+		Stats  []map[string]interface{} `json:"STATS"`
+	} `json:"stats"`
+}
+
+type cgminerStatus struct {
+	Status string `json:"STATUS"`
+	Msg    string `json:"Msg"`
+}
+
+// cgminerRates covers the hashrate fields, which depending on the fork and
+// the speed of the device come in GH/s, MH/s or KH/s.
+type cgminerRates struct {
+	GHSav *number `json:"GHS av"`
+	GHS5s *number `json:"GHS 5s"`
+	MHSav *number `json:"MHS av"`
+	MHS5s *number `json:"MHS 5s"`
+	KHSav *number `json:"KHS av"`
+	KHS5s *number `json:"KHS 5s"`
+}
+
+type cgminerSummary struct {
+	cgminerRates
+	Elapsed        number  `json:"Elapsed"`
+	Accepted       number  `json:"Accepted"`
+	Rejected       number  `json:"Rejected"`
+	Stale          number  `json:"Stale"`
+	HardwareErrors number  `json:"Hardware Errors"`
+	FanSpeedIn     *number `json:"Fan Speed In"`
+	FanSpeedOut    *number `json:"Fan Speed Out"`
+}
+
+type cgminerDevice struct {
+	cgminerRates
+	Name           string  `json:"Name"`
+	Temperature    *number `json:"Temperature"`
+	FanSpeed       *number `json:"Fan Speed"`
+	FanPercent     *number `json:"Fan Percent"`
+	GPUClock       *number `json:"GPU Clock"`
+	MemoryClock    *number `json:"Memory Clock"`
+	Accepted       number  `json:"Accepted"`
+	Rejected       number  `json:"Rejected"`
+	Stale          number  `json:"Stale"`
+	HardwareErrors *number `json:"Hardware Errors"`
+}
+
+type cgminerPool struct {
+	Pool           number  `json:"POOL"`
+	URL            string  `json:"URL"`
+	Status         string  `json:"Status"`
+	Priority       number  `json:"Priority"`
+	User           string  `json:"User"`
+	Algorithm      string  `json:"Algorithm"`
+	StratumActive  bool    `json:"Stratum Active"`
+	LastShareTime  *number `json:"Last Share Time"`
+	WorkDifficulty *number `json:"Work Difficulty"`
+}
+
+// number decodes numeric fields that some forks send as strings and turns
+// anything unparsable into zero instead of failing the whole reply.
+type number float64
+
+func (n *number) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseFloat(string(bytes.Trim(data, `"`)), 64)
+	if err != nil {
+		value = 0
+	}
+	*n = number(value)
+	return nil
+}
+
+func (n *number) reading() *float64 {
+	if n == nil {
+		return nil
+	}
+	return reading(float64(*n))
+}
+
+// NewCGMinerClient reports the given algorithm, or else the one announced by
+// the pool, since cgminer itself does not tell.
+func NewCGMinerClient(address string, algorithm string, asic bool) *CGMinerClient {
+	return &CGMinerClient{&cgminerAPIClient{address}, algorithm, asic}
+}
+
+func (c *CGMinerClient) Name() string {
+	return "cgminer"
+}
+
+func (c *CGMinerClient) Collect(ctx context.Context) (*Metrics, error) {
+	command := "summary+devs+pools+version"
+	if c.asic {
+		command = command + "+stats"
+	}
+
+	resp, err := c.api.Command(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := cgminerReply{}
+	if err := unmarshalCGMiner(resp, &reply); err != nil {
+		return nil, err
+	}
+
+	if len(reply.Summary) == 0 || len(reply.Summary[0].Summary) == 0 {
+		if len(reply.Summary) > 0 && len(reply.Summary[0].Status) > 0 {
+			return nil, fmt.Errorf("cgminer: %s", reply.Summary[0].Status[0].Msg)
+		}
+		return nil, errors.New("cgminer reply has no summary")
+	}
+	summary := reply.Summary[0].Summary[0]
+
+	byGPU := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
+	if len(reply.Devs) > 0 {
+		for _, dev := range reply.Devs[0].Devs {
+			byGPU = append(byGPU, dev.rate())
+			sharesByGPU = append(sharesByGPU, Shares{
+				Accepted: float64(dev.Accepted),
+				Rejected: float64(dev.Rejected),
+				Stale:    float64(dev.Stale),
+			})
+			gpus = append(gpus, GPU{
+				Card:           dev.Name,
+				Temperature:    dev.Temperature.reading(),
+				FanPercent:     dev.FanPercent.reading(),
+				FanRPM:         dev.FanSpeed.reading(),
+				CoreClock:      dev.GPUClock.reading(),
+				MemoryClock:    dev.MemoryClock.reading(),
+				HardwareErrors: dev.HardwareErrors.reading(),
+			})
+		}
+	}
+
+	algorithm := c.algorithm
+	pool := Pool{}
+	if len(reply.Pools) > 0 {
+		if p := activePool(reply.Pools[0].Pools); p != nil {
+			if algorithm == "" {
+				algorithm = p.Algorithm
+			}
+			pool = p.pool()
+		}
+	}
+	if algorithm == "" {
+		algorithm = "unknown"
+	}
+
+	version := ""
+	if len(reply.Version) > 0 && len(reply.Version[0].Version) > 0 {
+		version = cgminerVersion(reply.Version[0].Version[0])
+	}
+
+	boards := []Board{}
+	fans := []Fan{}
+	if len(reply.Stats) > 0 {
+		boards, fans = parseASICStats(reply.Stats[0].Stats)
+	}
+	fans = append(fans, summary.fans()...)
+
+	return &Metrics{
+		Version: version,
+		Uptime:  float64(summary.Elapsed),
+		Algorithms: []Algorithm{
+			{
+				Name: algorithm,
+				Shares: Shares{
+					Accepted: float64(summary.Accepted),
+					Rejected: float64(summary.Rejected),
+					Stale:    float64(summary.Stale),
+				},
+				Rates: Rates{
+					Total: summary.rate(),
+					ByGPU: byGPU,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool:        pool,
+			},
+		},
+		GPUs:   gpus,
+		Boards: boards,
+		Fans:   fans,
+	}, nil
+}
+
+// rate returns the current rate in H/s, preferring the 5s over the average
+// rate and whichever unit the fork uses.
+func (r cgminerRates) rate() float64 {
+	for _, field := range []struct {
+		value *number
+		scale float64
+	}{
+		{r.GHS5s, 1e9}, {r.MHS5s, 1e6}, {r.KHS5s, 1e3},
+		{r.GHSav, 1e9}, {r.MHSav, 1e6}, {r.KHSav, 1e3},
+	} {
+		if field.value != nil {
+			return float64(*field.value) * field.scale
+		}
+	}
+	return 0
+}
+
+// fans returns the intake and exhaust fans Whatsminers report in their
+// summary instead of the stats command.
+func (s cgminerSummary) fans() []Fan {
+	fans := []Fan{}
+	if s.FanSpeedIn != nil {
+		fans = append(fans, Fan{ID: "in", RPM: float64(*s.FanSpeedIn)})
+	}
+	if s.FanSpeedOut != nil {
+		fans = append(fans, Fan{ID: "out", RPM: float64(*s.FanSpeedOut)})
+	}
+	return fans
+}
+
+// activePool picks the pool the miner is currently working on: the one with
+// an active stratum connection, or else the alive pool of highest priority.
+func activePool(pools []cgminerPool) *cgminerPool {
+	var active *cgminerPool
+	for i := range pools {
+		p := &pools[i]
+		if p.StratumActive {
+			return p
+		}
+		if p.Status == "Alive" && (active == nil || p.Priority < active.Priority) {
+			active = p
+		}
+	}
+	return active
+}
+
+func (p *cgminerPool) pool() Pool {
+	pool := Pool{
+		URL:        p.URL,
+		User:       p.User,
+		Difficulty: p.WorkDifficulty.reading(),
+	}
+
+	if p.LastShareTime != nil && *p.LastShareTime > 0 {
+		pool.LastShare = reading(time.Since(time.Unix(int64(*p.LastShareTime), 0)).Seconds())
+	}
+
+	return pool
+}
+
+func cgminerVersion(version map[string]interface{}) string {
+	for _, key := range []string{"CGMiner", "SGMiner", "BFGMiner", "Miner"} {
+		if v, ok := version[key]; ok {
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	return ""
+}
+
+var (
+	cgminerConcatenated   = regexp.MustCompile(`}\s*{`)
+	cgminerTrailingCommas = regexp.MustCompile(`,\s*([}\]])`)
+	cgminerNonNumbers     = regexp.MustCompile(`:\s*-?(nan|inf)\b`)
+)
+
+// unmarshalCGMiner works around the invalid JSON some forks send: trailing
+// NUL bytes, objects concatenated without comma, trailing commas and bare
+// nan or inf values.
+func unmarshalCGMiner(data []byte, v interface{}) error {
+	data = bytes.TrimRight(data, "\x00 \r\n")
+	data = cgminerConcatenated.ReplaceAll(data, []byte("},{"))
+	data = cgminerTrailingCommas.ReplaceAll(data, []byte("$1"))
+	data = cgminerNonNumbers.ReplaceAll(data, []byte(":0"))
+
+	return json.Unmarshal(data, v)
+}
+
+func (c *cgminerAPIClient) Command(ctx context.Context, command string) ([]byte, error) {
+	conn, err := dial(ctx, "tcp", c.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	request, err := json.Marshal(map[string]string{"command": command})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = conn.Write(request); err != nil {
+		return nil, err
+	}
+
+	// The miner closes the connection after a single reply.
+	return ioutil.ReadAll(conn)
+}
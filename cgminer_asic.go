@@ -0,0 +1,105 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The stats command of Antminer firmware numbers its keys by chain and by
+// fan, e.g. chain_acs6 for the chip status of the hashboard on chain 6.
+var (
+	antminerChainKey = regexp.MustCompile(`^chain_acn(\d+)$`)
+	antminerFanKey   = regexp.MustCompile(`^fan(\d+)$`)
+)
+
+// parseASICStats reads hashboards and fans from the entries of a stats
+// reply. Keys differ by model, so whatever is not present is left out.
+func parseASICStats(stats []map[string]interface{}) ([]Board, []Fan) {
+	boards := []Board{}
+	fans := []Fan{}
+
+	for _, entry := range stats {
+		for key := range entry {
+			if match := antminerChainKey.FindStringSubmatch(key); match != nil {
+				if chips, ok := statsValue(entry[key]); ok && chips > 0 {
+					boards = append(boards, antminerBoard(entry, match[1]))
+				}
+			}
+
+			// Unused fan headers report zero, so only spinning fans
+			// are exported.
+			if match := antminerFanKey.FindStringSubmatch(key); match != nil {
+				if rpm, ok := statsValue(entry[key]); ok && rpm > 0 {
+					fans = append(fans, Fan{ID: match[1], RPM: rpm})
+				}
+			}
+		}
+	}
+
+	sort.Slice(boards, func(i, j int) bool { return byNumber(boards[i].ID, boards[j].ID) })
+	sort.Slice(fans, func(i, j int) bool { return byNumber(fans[i].ID, fans[j].ID) })
+
+	return boards, fans
+}
+
+func antminerBoard(entry map[string]interface{}, chain string) Board {
+	board := Board{ID: chain}
+
+	status, _ := entry["chain_acs"+chain].(string)
+	board.ChipsHealthy = float64(strings.Count(status, "o"))
+	board.ChipsFailed = float64(strings.Count(status, "x"))
+
+	board.Temperature = statsReading(entry, "temp"+chain, "temp_pcb"+chain)
+	board.ChipTemperature = statsReading(entry, "temp2_"+chain, "temp_chip"+chain)
+	board.Frequency = statsReading(entry, "freq_avg"+chain, "frequency")
+	board.HardwareErrors = statsReading(entry, "chain_hw"+chain)
+
+	// Chain rates are reported in GH/s.
+	if rate := statsReading(entry, "chain_rate"+chain); rate != nil {
+		board.Rate = reading(*rate * 1e9)
+	}
+
+	return board
+}
+
+// statsReading returns the first of the keys present. Newer firmware packs
+// several sensors into strings like "45-41-58-54", of which the hottest
+// is taken.
+func statsReading(entry map[string]interface{}, keys ...string) *float64 {
+	for _, key := range keys {
+		if value, ok := entry[key].(string); ok && strings.Contains(value, "-") {
+			max := 0.0
+			for _, v := range strings.Split(value, "-") {
+				if f, err := strconv.ParseFloat(v, 64); err == nil && f > max {
+					max = f
+				}
+			}
+			return reading(max)
+		}
+
+		if value, ok := statsValue(entry[key]); ok {
+			return reading(value)
+		}
+	}
+	return nil
+}
+
+func statsValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// byNumber orders chains and fans numerically, so fan10 follows fan9.
+func byNumber(a, b string) bool {
+	x, _ := strconv.Atoi(a)
+	y, _ := strconv.Atoi(b)
+	return x < y
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	// sgminer 5 with its known quirks: pools concatenated without comma,
+	// a trailing comma, a bare nan and the trailing NUL byte.
+	SGMINER_REPLY = `{"summary":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":11,"Msg":"Summary","Description":"sgminer 5.6.1"}],"SUMMARY":[{"Elapsed":5400,"MHS av":30.512,"MHS 5s":30.488,"Found Blocks":0,"Getworks":190,"Accepted":312,"Rejected":4,"Hardware Errors":0,"Utility":3.467,"Discarded":380,"Stale":2,"Get Failures":0,"Local Work":4302,"Remote Failures":0,"Network Blocks":95,"Total MH":164764.6,"Work Utility":29.811,"Difficulty Accepted":19968.0,"Difficulty Rejected":256.0,"Difficulty Stale":128.0,"Best Share":85213,"Device Hardware%":0.0,"Device Rejected%":1.2658,"Pool Rejected%":1.2658,"Pool Stale%":0.6329,"Last getwork":1518364734}],"id":1}],` +
+		`"devs":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":9,"Msg":"2 GPU(s)","Description":"sgminer 5.6.1"}],"DEVS":[{"GPU":0,"Enabled":"Y","Status":"Alive","Temperature":71.0,"Fan Speed":2411,"Fan Percent":55,"GPU Clock":1150,"Memory Clock":1500,"GPU Voltage":1.150,"GPU Activity":99,"Powertune":0,"MHS av":15.260,"MHS 5s":15.251,"Accepted":160,"Rejected":2,"Hardware Errors":0,"Utility":1.778,"Intensity":"20","Last Share Pool":0,"Last Share Time":1518364730,"Total MH":82401.2,"Diff1 Work":10240,"Difficulty Accepted":10240.0,"Difficulty Rejected":128.0,"Last Share Difficulty":64.0,"Last Valid Work":1518364733,"Device Hardware%":0.0,"Device Rejected%":1.2346,"Device Elapsed":5400},` +
+		`{"GPU":1,"Enabled":"Y","Status":"Alive","Temperature":nan,"Fan Speed":2390,"Fan Percent":54,"GPU Clock":1150,"Memory Clock":1500,"GPU Voltage":1.150,"GPU Activity":99,"Powertune":0,"MHS av":15.252,"MHS 5s":15.237,"Accepted":152,"Rejected":2,"Hardware Errors":0,"Utility":1.689,"Intensity":"20","Last Share Pool":0,"Last Share Time":1518364722,"Total MH":82363.4,"Diff1 Work":9728,"Difficulty Accepted":9728.0,"Difficulty Rejected":128.0,"Last Share Difficulty":64.0,"Last Valid Work":1518364733,"Device Hardware%":0.0,"Device Rejected%":1.3158,"Device Elapsed":5400},],"id":1}],` +
+		`"pools":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":7,"Msg":"2 Pool(s)","Description":"sgminer 5.6.1"}],"POOLS":[{"POOL":0,"Name":"hub","URL":"stratum+tcp://hub.miningpoolhub.com:12005","Profile":"","Algorithm":"lyra2rev2","Algorithm Type":"Lyra2RE","Status":"Alive","Priority":0,"Quota":1,"Long Poll":"N","Getworks":190,"Accepted":312,"Rejected":4,"Works":4302,"Discarded":380,"Stale":2,"Get Failures":0,"Remote Failures":0,"User":"bugroger.wupse","Last Share Time":0,"Diff1 Shares":19968,"Proxy Type":"","Proxy":"","Difficulty Accepted":19968.0,"Difficulty Rejected":256.0,"Difficulty Stale":128.0,"Last Share Difficulty":64.0,"Has Stratum":true,"Stratum Active":true,"Stratum URL":"hub.miningpoolhub.com","Has GBT":false,"Best Share":85213,"Pool Rejected%":1.2658,"Pool Stale%":0.6329}{"POOL":1,"Name":"backup","URL":"stratum+tcp://backup.miningpoolhub.com:12005","Algorithm":"lyra2rev2","Status":"Alive","Priority":1,"Accepted":0,"Rejected":0,"Stale":0,"User":"bugroger.wupse","Last Share Time":0,"Stratum Active":false}],"id":1}],` +
+		`"version":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":22,"Msg":"SGMiner versions","Description":"sgminer 5.6.1"}],"VERSION":[{"SGMiner":"5.6.1","API":"4.0"}],"id":1}],"id":1}` + "\x00"
+
+	// Antminer S9 reporting its chains as ASC devices in GH/s.
+	ANTMINER_REPLY = `{"summary":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":11,"Msg":"Summary","Description":"cgminer 4.9.0"}],"SUMMARY":[{"Elapsed":86400,"GHS 5s":"13712.45","GHS av":13690.12,"Found Blocks":0,"Getworks":5812,"Accepted":43120,"Rejected":21,"Hardware Errors":118,"Utility":29.94,"Discarded":213440,"Stale":3,"Get Failures":0,"Local Work":1402933,"Remote Failures":0,"Network Blocks":150,"Total MH":1182826368000.0,"Work Utility":191208.1,"Difficulty Accepted":274989056.0,"Difficulty Rejected":131072.0,"Difficulty Stale":0.0,"Best Share":2147483648,"Device Hardware%":0.0004,"Device Rejected%":0.0476,"Pool Rejected%":0.0476,"Pool Stale%":0.0,"Last getwork":0}],"id":1}],` +
+		`"devs":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":9,"Msg":"3 ASC(s)","Description":"cgminer 4.9.0"}],"DEVS":[{"ASC":0,"Name":"BTM","ID":0,"Enabled":"Y","Status":"Alive","Temperature":62.00,"MHS av":4563372.00,"MHS 5s":4571484.00,"Accepted":14373,"Rejected":7,"Hardware Errors":40,"Utility":9.98,"Last Share Pool":0,"Last Share Time":0,"Total MH":0.0,"Diff1 Work":0,"Difficulty Accepted":0.0,"Difficulty Rejected":0.0,"Last Share Difficulty":0.0,"Last Valid Work":0,"Device Hardware%":0.0,"Device Rejected%":0.0,"Device Elapsed":86400},` +
+		`{"ASC":1,"Name":"BTM","ID":1,"Enabled":"Y","Status":"Alive","Temperature":64.00,"MHS av":4563372.00,"MHS 5s":4570483.00,"Accepted":14373,"Rejected":7,"Hardware Errors":39,"Device Elapsed":86400},` +
+		`{"ASC":2,"Name":"BTM","ID":2,"Enabled":"Y","Status":"Alive","Temperature":63.00,"MHS av":4563372.00,"MHS 5s":4570483.00,"Accepted":14374,"Rejected":7,"Hardware Errors":39,"Device Elapsed":86400}],"id":1}],` +
+		`"pools":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":7,"Msg":"1 Pool(s)","Description":"cgminer 4.9.0"}],"POOLS":[{"POOL":0,"URL":"stratum+tcp://stratum.antpool.com:3333","Status":"Alive","Priority":0,"Quota":1,"Long Poll":"N","Getworks":5812,"Accepted":43120,"Rejected":21,"Discarded":213440,"Stale":3,"Get Failures":0,"Remote Failures":0,"User":"bugroger.s9","Last Share Time":"0:00:12","Diff":"8.19K","Diff1 Shares":0,"Proxy Type":"","Proxy":"","Difficulty Accepted":274989056.0,"Difficulty Rejected":131072.0,"Difficulty Stale":0.0,"Last Share Difficulty":8192.0,"Has Stratum":true,"Stratum Active":true,"Stratum URL":"stratum.antpool.com","Has GBT":false,"Best Share":2147483648,"Pool Rejected%":0.0476,"Pool Stale%":0.0}],"id":1}],` +
+		`"version":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":22,"Msg":"CGMiner versions","Description":"cgminer 4.9.0"}],"VERSION":[{"CGMiner":"4.9.0","API":"3.1","Miner":"16.8.1.3","CompileTime":"Fri Aug 25 17:29:46 CST 2017","Type":"Antminer S9"}],"id":1}],"id":1}`
+
+	// The stats section of the same Antminer S9, with chains 6 to 8 in use.
+	ANTMINER_STATS = `"stats":[{"STATUS":[{"STATUS":"S","When":1518364735,"Code":70,"Msg":"CGMiner stats","Description":"cgminer 4.9.0"}],"STATS":[{"CGMiner":"4.9.0","Miner":"16.8.1.3","CompileTime":"Fri Aug 25 17:29:46 CST 2017","Type":"Antminer S9"},` +
+		`{"STATS":0,"ID":"BC50","Elapsed":86400,"Calls":0,"Wait":0.000000,"Max":0.000000,"Min":99999999.000000,"GHS 5s":"13712.45","GHS av":13690.12,"miner_count":3,"frequency":"650","fan_num":2,"fan1":0,"fan2":0,"fan3":5760,"fan4":0,"fan5":0,"fan6":5880,"fan7":0,"fan8":0,` +
+		`"temp_num":3,"temp1":0,"temp2":0,"temp3":0,"temp4":0,"temp5":0,"temp6":60,"temp7":62,"temp8":61,"temp2_1":0,"temp2_2":0,"temp2_3":0,"temp2_4":0,"temp2_5":0,"temp2_6":75,"temp2_7":77,"temp2_8":76,"temp_max":62,"Device Hardware%":0.0004,"no_matching_work":118,` +
+		`"chain_acn1":0,"chain_acn2":0,"chain_acn3":0,"chain_acn4":0,"chain_acn5":0,"chain_acn6":63,"chain_acn7":63,"chain_acn8":63,` +
+		`"chain_acs1":"","chain_acs2":"","chain_acs3":"","chain_acs4":"","chain_acs5":"",` +
+		`"chain_acs6":" oooooooo oooooooo oooooooo oooooooo oooooooo oooooooo oooooooo ooooooo",` +
+		`"chain_acs7":" oooooooo oooooooo ooooxooo oooooooo oooooooo oooooooo oooooooo ooooooo",` +
+		`"chain_acs8":" oooooooo oooooooo oooooooo oooooooo xxoooooo oooooooo oooooooo ooooooo",` +
+		`"chain_hw1":0,"chain_hw2":0,"chain_hw3":0,"chain_hw4":0,"chain_hw5":0,"chain_hw6":40,"chain_hw7":39,"chain_hw8":39,` +
+		`"chain_rate1":"","chain_rate2":"","chain_rate3":"","chain_rate4":"","chain_rate5":"","chain_rate6":"4571.48","chain_rate7":"4570.48","chain_rate8":"4570.49",` +
+		`"freq_avg1":0.00,"freq_avg2":0.00,"freq_avg3":0.00,"freq_avg4":0.00,"freq_avg5":0.00,"freq_avg6":650.00,"freq_avg7":650.00,"freq_avg8":650.00}],"id":1}]`
+)
+
+type MockedCGMinerAPI struct {
+	mock.Mock
+}
+
+func (m *MockedCGMinerAPI) Command(ctx context.Context, command string) ([]byte, error) {
+	args := m.Called(command)
+	return []byte(args.String(0)), args.Error(1)
+}
+
+func TestCGMinerCollectSGMiner(t *testing.T) {
+	mockAPI := new(MockedCGMinerAPI)
+
+	mockAPI.On("Command", "summary+devs+pools+version").Return(SGMINER_REPLY, nil)
+
+	miner := &CGMinerClient{api: mockAPI}
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "cgminer", miner.Name())
+	assert.Equal(t, "5.6.1", metrics.Version)
+	assert.Equal(t, 5400.0, metrics.Uptime)
+	assert.Equal(t, "lyra2rev2", metrics.Algorithms[0].Name)
+	assert.Equal(t, 312.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 4.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].Shares.Stale)
+	assert.Equal(t, 30488000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{15251000, 15237000}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, 152.0, metrics.Algorithms[0].SharesByGPU[1].Accepted)
+	assert.Equal(t, "stratum+tcp://hub.miningpoolhub.com:12005", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "bugroger.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Nil(t, metrics.Algorithms[0].Pool.LastShare)
+	assert.Equal(t, 71.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 0.0, *metrics.GPUs[1].Temperature)
+	assert.Equal(t, 2411.0, *metrics.GPUs[0].FanRPM)
+	assert.Equal(t, 55.0, *metrics.GPUs[0].FanPercent)
+	assert.Equal(t, 1150.0, *metrics.GPUs[0].CoreClock)
+	assert.Equal(t, 1500.0, *metrics.GPUs[0].MemoryClock)
+}
+
+func TestCGMinerCollectAntminer(t *testing.T) {
+	mockAPI := new(MockedCGMinerAPI)
+
+	mockAPI.On("Command", "summary+devs+pools+version").Return(ANTMINER_REPLY, nil)
+
+	miner := &CGMinerClient{api: mockAPI, algorithm: "sha256d"}
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "4.9.0", metrics.Version)
+	assert.Equal(t, "sha256d", metrics.Algorithms[0].Name)
+	assert.Equal(t, 13712450000000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, 3, len(metrics.Algorithms[0].Rates.ByGPU))
+	assert.Equal(t, 4571484000000.0, metrics.Algorithms[0].Rates.ByGPU[0])
+	assert.Equal(t, "BTM", metrics.GPUs[0].Card)
+	assert.Equal(t, 40.0, *metrics.GPUs[0].HardwareErrors)
+	assert.Nil(t, metrics.GPUs[0].FanRPM)
+	assert.Equal(t, "bugroger.s9", metrics.Algorithms[0].Pool.User)
+}
+
+func TestCGMinerCollectError(t *testing.T) {
+	mockAPI := new(MockedCGMinerAPI)
+
+	mockAPI.On("Command", "summary+devs+pools+version").Return(`{"STATUS":[{"STATUS":"E","When":1518364735,"Code":14,"Msg":"Invalid command","Description":"cgminer 4.9.0"}],"id":1}`, nil)
+
+	_, err := (&CGMinerClient{api: mockAPI}).Collect(context.Background())
+
+	assert.NotNil(t, err)
+}
+
+func TestCGMinerCollectAntminerStats(t *testing.T) {
+	mockAPI := new(MockedCGMinerAPI)
+
+	reply := strings.TrimSuffix(ANTMINER_REPLY, `"id":1}`) + ANTMINER_STATS + `,"id":1}`
+	mockAPI.On("Command", "summary+devs+pools+version+stats").Return(reply, nil)
+
+	miner := &CGMinerClient{api: mockAPI, asic: true}
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(metrics.Boards))
+	assert.Equal(t, "6", metrics.Boards[0].ID)
+	assert.Equal(t, 63.0, metrics.Boards[0].ChipsHealthy)
+	assert.Equal(t, 0.0, metrics.Boards[0].ChipsFailed)
+	assert.Equal(t, 62.0, metrics.Boards[1].ChipsHealthy)
+	assert.Equal(t, 1.0, metrics.Boards[1].ChipsFailed)
+	assert.Equal(t, 2.0, metrics.Boards[2].ChipsFailed)
+	assert.Equal(t, 60.0, *metrics.Boards[0].Temperature)
+	assert.Equal(t, 75.0, *metrics.Boards[0].ChipTemperature)
+	assert.Equal(t, 650.0, *metrics.Boards[0].Frequency)
+	assert.Equal(t, 4571480000000.0, *metrics.Boards[0].Rate)
+	assert.Equal(t, 40.0, *metrics.Boards[0].HardwareErrors)
+	assert.Equal(t, []Fan{{ID: "3", RPM: 5760}, {ID: "6", RPM: 5880}}, metrics.Fans)
+}
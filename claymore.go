@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClaymoreClient speaks the miner_getstat1 protocol of Claymore's miners,
+// which PhoenixMiner adopted as well. The variant decides what the reply
+// means: which algorithms are mined and in which unit rates are reported.
+type ClaymoreClient struct {
+	address  string
+	password string
+	variant  claymoreVariant
+
+	// secondary names the dual mining algorithm, which is reported even
+	// while it is idle.
+	secondary string
+
+	// getstat2 asks for miner_getstat2, which adds the shares of each GPU.
+	getstat2 bool
+
+	// files are read with miner_getfile to report a hash of their content.
+	// The hashes are kept for claymoreFilesInterval, as files rarely change.
+	files    []string
+	mu       sync.Mutex
+	hashes   []File
+	hashedAt time.Time
+}
+
+// claymoreFilesInterval is how long hashes of files read from the miner
+// are reported before the files are read again.
+const claymoreFilesInterval = 10 * time.Minute
+
+type claymoreVariant struct {
+	name      string
+	primary   string
+	secondary string
+
+	// scale turns the reported rates into H/s.
+	scale float64
+}
+
+var claymoreVariants = map[string]claymoreVariant{
+	"eth":        {"ClaymoreDualMiner", "daggerhashimoto", "decred", 1e3},
+	"phoenix":    {"PhoenixMiner", "daggerhashimoto", "blake2s", 1e3},
+	"cryptonote": {"ClaymoreCryptoNote", "cryptonight", "", 1},
+	"zcash":      {"ClaymoreZCash", "equihash", "", 1},
+}
+
+type claymoreRequest struct {
+	ID       int           `json:"id"`
+	JSONRPC  string        `json:"jsonrpc"`
+	Method   string        `json:"method"`
+	Params   []interface{} `json:"params,omitempty"`
+	Password string        `json:"psw,omitempty"`
+}
+
+type claymoreReply struct {
+	ID     int         `json:"id"`
+	Result []string    `json:"result"`
+	Error  interface{} `json:"error"`
+}
+
+// NewClaymoreClient reads a miner at the address of the target, with the
+// Claymore options of the target: the variant, "eth" if empty, names the
+// miner, while Algorithm and Secondary override the names of its algorithms;
+// a secondary is then always reported. The password is the one the miner
+// was started with using -mpsw, which reading files requires.
+func NewClaymoreClient(t TargetConfig) (*ClaymoreClient, error) {
+	variant := t.Variant
+	if variant == "" {
+		variant = "eth"
+	}
+
+	v, ok := claymoreVariants[variant]
+	if !ok {
+		return nil, fmt.Errorf("unknown claymore variant %q", variant)
+	}
+
+	if t.Algorithm != "" {
+		v.primary = t.Algorithm
+	}
+
+	return &ClaymoreClient{
+		address:   t.Address,
+		password:  t.Password,
+		variant:   v,
+		secondary: t.Secondary,
+		getstat2:  t.Getstat2,
+		files:     t.Files,
+	}, nil
+}
+
+func (c *ClaymoreClient) Name() string {
+	return c.variant.name
+}
+
+func (m *ClaymoreClient) Collect(ctx context.Context) (*Metrics, error) {
+	method := "miner_getstat1"
+	if m.getstat2 {
+		method = "miner_getstat2"
+	}
+
+	reply, err := m.call(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reply) < 8 {
+		return nil, fmt.Errorf("%s: reply has only %d fields", method, len(reply))
+	}
+
+	metrics, err := m.parse(reply)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", method, err)
+	}
+
+	metrics.Files = m.hashFiles(ctx)
+
+	return metrics, nil
+}
+
+// hashFiles returns the hashes of the files, reading them again once they
+// are older than claymoreFilesInterval. Files that cannot be read are left
+// out, as the miner itself is fine.
+func (m *ClaymoreClient) hashFiles(ctx context.Context) []File {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if time.Since(m.hashedAt) < claymoreFilesInterval {
+		return m.hashes
+	}
+
+	hashes := []File{}
+	for _, name := range m.files {
+		content, err := m.GetFile(ctx, name)
+		if err != nil {
+			log.Printf("Failed to read %s from %s, leaving it out: %s\n", name, m.address, err)
+			continue
+		}
+		sum := sha256.Sum256(content)
+		hashes = append(hashes, File{Name: name, SHA256: hex.EncodeToString(sum[:])})
+	}
+
+	m.hashes, m.hashedAt = hashes, time.Now()
+	return hashes
+}
+
+func (m *ClaymoreClient) call(ctx context.Context, method string, params ...interface{}) ([]string, error) {
+	request := claymoreRequest{ID: 0, JSONRPC: "2.0", Method: method, Params: params, Password: m.password}
+
+	reply := claymoreReply{}
+	if err := callJSON(ctx, m.address, request, &reply); err != nil {
+		// Claymore hangs up without a reply on requests with the wrong
+		// password. Without a password, that is just a lost connection.
+		if m.password != "" && errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, &authError{"miner closed the connection, check the API password"}
+		}
+		return nil, err
+	}
+
+	if reply.Error != nil {
+		message := fmt.Sprintf("%v", reply.Error)
+		if strings.Contains(strings.ToLower(message), "password") {
+			return nil, &authError{message}
+		}
+		return nil, fmt.Errorf("%s: %s", method, message)
+	}
+
+	return reply.Result, nil
+}
+
+// GetFile reads a file like config.txt from the directory of the miner.
+func (m *ClaymoreClient) GetFile(ctx context.Context, name string) ([]byte, error) {
+	reply, err := m.call(ctx, "miner_getfile", name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reply holds the name and the hex encoded content.
+	if len(reply) < 2 {
+		return nil, fmt.Errorf("miner_getfile: no content for %s", name)
+	}
+
+	return hex.DecodeString(reply[1])
+}
+
+// Control sends a command like miner_restart. The miner does not answer
+// control commands, so all there is to check is that it was sent.
+func (m *ClaymoreClient) Control(ctx context.Context, method string, params ...interface{}) error {
+	conn, err := dial(ctx, "tcp", m.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	request, err := json.Marshal(claymoreRequest{ID: 0, JSONRPC: "2.0", Method: method, Params: params, Password: m.password})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write(append(request, '\n'))
+	return err
+}
+
+func (m *ClaymoreClient) parse(reply []string) (*Metrics, error) {
+	version := reply[0]
+	uptime := parseGarble(reply[1])[0] * 60
+	primary := parseGarble(reply[2])
+	if len(primary) < 3 {
+		return nil, fmt.Errorf("garbled rate and shares %q", reply[2])
+	}
+	primaryRates := m.scale(parseGarble(reply[3]))
+	// Miners without dual mining may leave the secondary fields short.
+	alt := append(parseGarble(reply[4]), 0, 0, 0)
+	altRates := m.scale(parseGarble(reply[5]))
+	temps, fans := parseZippedGarble(reply[6])
+	pools := strings.Split(reply[7], ";")
+
+	// Field 8 holds the invalid shares and pool switches of both
+	// algorithms, which very old versions leave out.
+	invalid := []float64{0, 0}
+	switches := []*float64{nil, nil}
+	if len(reply) > 8 {
+		extra := append(parseGarble(reply[8]), 0, 0, 0, 0)
+		invalid = []float64{extra[0], extra[2]}
+		switches = []*float64{reading(extra[1]), reading(extra[3])}
+	}
+
+	gpus := []GPU{}
+	for i := range temps {
+		gpus = append(gpus, GPU{
+			Temperature: reading(temps[i]),
+			FanPercent:  reading(fans[i]),
+		})
+	}
+
+	algorithms := []Algorithm{
+		{
+			Name: m.variant.primary,
+			Shares: Shares{
+				Accepted: primary[1],
+				Rejected: primary[2],
+				Invalid:  invalid[0],
+			},
+			Rates: Rates{
+				Total: primary[0] * m.variant.scale,
+				ByGPU: primaryRates,
+			},
+			SharesByGPU: parseSharesByGPU(reply, 9),
+			Pool:        Pool{URL: pools[0], Switches: switches[0]},
+		},
+	}
+
+	// Without dual mining the secondary fields are all zero.
+	name := m.secondary
+	if name == "" && alt[0] > 0 {
+		name = m.variant.secondary
+	}
+
+	if name != "" {
+		algorithms = append(algorithms, Algorithm{
+			Name: name,
+			Shares: Shares{
+				Accepted: alt[1],
+				Rejected: alt[2],
+				Invalid:  invalid[1],
+			},
+			Rates: Rates{
+				Total: alt[0] * m.variant.scale,
+				ByGPU: altRates,
+			},
+			SharesByGPU: parseSharesByGPU(reply, 12),
+			Pool:        Pool{URL: secondary(pools), Switches: switches[1]},
+		})
+	}
+
+	return &Metrics{
+		Version:    version,
+		Uptime:     uptime,
+		Algorithms: algorithms,
+		GPUs:       gpus,
+	}, nil
+}
+
+func (m *ClaymoreClient) scale(rates []float64) []float64 {
+	for i := range rates {
+		rates[i] = rates[i] * m.variant.scale
+	}
+	return rates
+}
+
+func secondary(fields []string) string {
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// parseSharesByGPU reads the per-GPU accepted, rejected and invalid shares
+// that miner_getstat2 appends to the reply, starting at the given field.
+func parseSharesByGPU(reply []string, field int) []Shares {
+	if len(reply) < field+2 {
+		return nil
+	}
+
+	accepted := parseGarble(reply[field])
+	rejected := parseGarble(reply[field+1])
+	invalid := []float64{}
+	if len(reply) > field+2 {
+		invalid = parseGarble(reply[field+2])
+	}
+
+	shares := []Shares{}
+	for i := range accepted {
+		s := Shares{Accepted: accepted[i]}
+		if i < len(rejected) {
+			s.Rejected = rejected[i]
+		}
+		if i < len(invalid) {
+			s.Invalid = invalid[i]
+		}
+		shares = append(shares, s)
+	}
+
+	return shares
+}
+
+func unzip(i []string) ([]string, []string) {
+	a := []string{}
+	b := []string{}
+
+	for len(i) > 1 {
+		a = append(a, i[0])
+		b = append(b, i[1])
+		i = i[2:]
+	}
+
+	return a, b
+}
+
+func parseZippedGarble(input string) ([]float64, []float64) {
+	a, b := unzip(strings.Split(input, ";"))
+	return toFloat(a...), toFloat(b...)
+}
+
+func parseGarble(input string) []float64 {
+	return toFloat(strings.Split(input, ";")...)
+}
+
+func toFloat(input ...string) []float64 {
+	r := []float64{}
+	for _, i := range input {
+		f, _ := strconv.ParseFloat(i, 64)
+		r = append(r, f)
+	}
+	return r
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	GETSTAT1 = []string{
+		"10.0 - ETH",
+		"83",
+		"67664;48;0",
+		"28076;20040;19548",
+		"891200;12;0",
+		"297000;297000;297200",
+		"65;45;70;55;61;40",
+		"eu1.ethermine.org:4444;decred.suprnova.cc:3252",
+		"2;1;0;0",
+	}
+
+	PHOENIX_GETSTAT1 = []string{
+		"5.5c - ETH",
+		"241",
+		"61200;530;2",
+		"30600;30600",
+		"0;0;0",
+		"off;off",
+		"62;55;64;60",
+		"eu1.ethermine.org:4444",
+		"0;0;0;0",
+	}
+
+	CRYPTONOTE_GETSTAT1 = []string{
+		"11.3 - XMR",
+		"30",
+		"1620;95;1",
+		"810;810",
+		"0;0;0",
+		"off;off",
+		"70;80;68;75",
+		"pool.supportxmr.com:5555",
+	}
+)
+
+func TestClaymoreDualMinerParse(t *testing.T) {
+	miner, _ := NewClaymoreClient(TargetConfig{Address: "localhost:3333"})
+	metrics, _ := miner.parse(GETSTAT1)
+
+	assert.Equal(t, "10.0 - ETH", metrics.Version)
+	assert.Equal(t, 4980.0, metrics.Uptime)
+	assert.Equal(t, "daggerhashimoto", metrics.Algorithms[0].Name)
+	assert.Equal(t, 48.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 67664000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, 20040000.0, metrics.Algorithms[0].Rates.ByGPU[1])
+	assert.Equal(t, 3, len(metrics.GPUs))
+	assert.Equal(t, 70.0, *metrics.GPUs[1].Temperature)
+	assert.Equal(t, 55.0, *metrics.GPUs[1].FanPercent)
+	assert.Equal(t, 40.0, *metrics.GPUs[2].FanPercent)
+	assert.Nil(t, metrics.Algorithms[0].SharesByGPU)
+	assert.Equal(t, "eu1.ethermine.org:4444", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].Shares.Invalid)
+	assert.Equal(t, 1.0, *metrics.Algorithms[0].Pool.Switches)
+	assert.Equal(t, "decred", metrics.Algorithms[1].Name)
+	assert.Equal(t, 0.0, metrics.Algorithms[1].Shares.Invalid)
+	assert.Equal(t, 0.0, *metrics.Algorithms[1].Pool.Switches)
+	assert.Equal(t, 891200000.0, metrics.Algorithms[1].Rates.Total)
+	assert.Equal(t, "decred.suprnova.cc:3252", metrics.Algorithms[1].Pool.URL)
+}
+
+func TestClaymoreParseWithoutDualMining(t *testing.T) {
+	miner, _ := NewClaymoreClient(TargetConfig{Address: "localhost:3333", Variant: "phoenix"})
+	metrics, _ := miner.parse(PHOENIX_GETSTAT1)
+
+	assert.Equal(t, "PhoenixMiner", miner.Name())
+	assert.Equal(t, "5.5c - ETH", metrics.Version)
+	assert.Equal(t, 1, len(metrics.Algorithms))
+	assert.Equal(t, "daggerhashimoto", metrics.Algorithms[0].Name)
+	assert.Equal(t, 61200000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, 2, len(metrics.GPUs))
+
+	miner, _ = NewClaymoreClient(TargetConfig{Address: "localhost:3333", Variant: "phoenix", Secondary: "blake2s"})
+	metrics, _ = miner.parse(PHOENIX_GETSTAT1)
+
+	assert.Equal(t, 2, len(metrics.Algorithms))
+	assert.Equal(t, "blake2s", metrics.Algorithms[1].Name)
+	assert.Equal(t, 0.0, metrics.Algorithms[1].Rates.Total)
+}
+
+func TestClaymoreParseVariants(t *testing.T) {
+	miner, _ := NewClaymoreClient(TargetConfig{Address: "localhost:3333", Variant: "cryptonote"})
+	metrics, _ := miner.parse(CRYPTONOTE_GETSTAT1)
+
+	assert.Equal(t, "ClaymoreCryptoNote", miner.Name())
+	assert.Nil(t, metrics.Algorithms[0].Pool.Switches)
+	assert.Equal(t, 1, len(metrics.Algorithms))
+	assert.Equal(t, "cryptonight", metrics.Algorithms[0].Name)
+	assert.Equal(t, 1620.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{810, 810}, metrics.Algorithms[0].Rates.ByGPU)
+
+	miner, _ = NewClaymoreClient(TargetConfig{Address: "localhost:3333", Variant: "zcash", Algorithm: "equihash200_9"})
+	metrics, _ = miner.parse(CRYPTONOTE_GETSTAT1)
+
+	assert.Equal(t, "ClaymoreZCash", miner.Name())
+	assert.Equal(t, "equihash200_9", metrics.Algorithms[0].Name)
+
+	_, err := NewClaymoreClient(TargetConfig{Address: "localhost:3333", Variant: "bogus"})
+	assert.NotNil(t, err)
+}
+
+func TestClaymoreParseGarbled(t *testing.T) {
+	miner, _ := NewClaymoreClient(TargetConfig{Address: "localhost:3333"})
+
+	reply := append([]string{}, GETSTAT1...)
+	reply[2] = ""
+	_, err := miner.parse(reply)
+	assert.NotNil(t, err)
+
+	reply = append([]string{}, GETSTAT1...)
+	reply[4] = ""
+	metrics, err := miner.parse(reply)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(metrics.Algorithms))
+}
+
+// claymoreServer answers requests like a miner started with -mpsw secret,
+// hanging up on requests without the password. Files read with
+// miner_getfile contain their own name, except for missing.txt.
+func claymoreServer(t *testing.T, reply []string) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			request := claymoreRequest{}
+			line, _ := bufio.NewReader(conn).ReadBytes('\n')
+			json.Unmarshal(line, &request)
+
+			if request.Password == "secret" {
+				result, _ := json.Marshal(reply)
+				if request.Method == "miner_getfile" {
+					name := request.Params[0].(string)
+					result, _ = json.Marshal([]string{name, hex.EncodeToString([]byte(name))})
+					if name == "missing.txt" {
+						conn.Write([]byte(`{"id":0,"result":null,"error":"file not found"}` + "\n"))
+						conn.Close()
+						continue
+					}
+				}
+				conn.Write([]byte(`{"id":0,"result":` + string(result) + `,"error":null}` + "\n"))
+			}
+			conn.Close()
+		}
+	}()
+
+	return listener
+}
+
+func TestClaymoreCollectGetstat2(t *testing.T) {
+	listener := claymoreServer(t, append(GETSTAT1,
+		"40;8", "1;0", "0;2",
+		"6;6", "0;0", "0;0",
+	))
+	defer listener.Close()
+
+	miner, _ := NewClaymoreClient(TargetConfig{Address: listener.Addr().String(), Password: "secret", Getstat2: true})
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 48.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, Shares{Accepted: 8, Rejected: 0, Invalid: 2}, metrics.Algorithms[0].SharesByGPU[1])
+	assert.Equal(t, Shares{Accepted: 6}, metrics.Algorithms[1].SharesByGPU[0])
+}
+
+func TestClaymoreCollectWrongPassword(t *testing.T) {
+	listener := claymoreServer(t, GETSTAT1)
+	defer listener.Close()
+
+	miner, _ := NewClaymoreClient(TargetConfig{Address: listener.Addr().String(), Password: "wrong"})
+	_, err := miner.Collect(context.Background())
+
+	assert.IsType(t, &authError{}, err)
+	assert.Equal(t, "auth", errorReason(context.Background(), err))
+
+	// Without a password the miner just went away.
+	miner, _ = NewClaymoreClient(TargetConfig{Address: listener.Addr().String()})
+	_, err = miner.Collect(context.Background())
+
+	assert.Equal(t, "connection", errorReason(context.Background(), err))
+}
+
+func TestClaymoreCollectFiles(t *testing.T) {
+	listener := claymoreServer(t, GETSTAT1)
+	defer listener.Close()
+
+	miner, _ := NewClaymoreClient(TargetConfig{Address: listener.Addr().String(), Password: "secret", Files: []string{"config.txt", "missing.txt", "epools.txt"}})
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []File{
+		{Name: "config.txt", SHA256: "a9b5c214b62651a1af8e7f600485ee6b280c815745eabb52c06bbccb2397b5f8"},
+		{Name: "epools.txt", SHA256: "305e4f3d0fe7602a5ca308b86ee34415b47b5bbfda59ebc41e3f170b855c5832"},
+	}, metrics.Files)
+}
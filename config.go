@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/prometheus/common/model"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type Config struct {
+	Targets []TargetConfig `yaml:"targets"`
+}
+
+type TargetConfig struct {
+	Type    string            `yaml:"type"`
+	Address string            `yaml:"address"`
+	Name    string            `yaml:"name"`
+	Labels  map[string]string `yaml:"labels"`
+
+	// Algorithm names the mined algorithm for miners that do not report it.
+	Algorithm string `yaml:"algorithm"`
+
+	// Variant selects the flavour of Claymore protocol miners: eth, phoenix,
+	// cryptonote or zcash.
+	Variant string `yaml:"variant"`
+
+	// Secondary names the dual mining algorithm of Claymore protocol
+	// miners, which is then reported even while idle.
+	Secondary string `yaml:"secondary"`
+
+	// Password is sent to Claymore protocol miners started with -mpsw.
+	Password string `yaml:"password"`
+
+	// Getstat2 reads Claymore protocol miners with miner_getstat2, which
+	// adds the shares of each GPU.
+	Getstat2 bool `yaml:"getstat2"`
+
+	// Files lists configuration files like config.txt or epools.txt to read
+	// from Claymore protocol miners, exporting a hash of their content.
+	Files []string `yaml:"files"`
+
+	// ASIC reads hashboard and fan details of cgminer based ASICs.
+	ASIC bool `yaml:"asic"`
+
+	// Token authenticates against miner APIs that require one, like XMRig.
+	Token string `yaml:"token"`
+
+	// PollInterval collects the miner in the background instead of on
+	// every scrape. Zero falls back to the -poll.interval flag.
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+var minerTypes = map[string]func(TargetConfig) (Miner, error){
+	"bminer": func(t TargetConfig) (Miner, error) {
+		return NewBminerClient(t.Address, t.Algorithm), nil
+	},
+	"ccminer": func(t TargetConfig) (Miner, error) {
+		return NewCCMinerClient(t.Address), nil
+	},
+	"cgminer": func(t TargetConfig) (Miner, error) {
+		return NewCGMinerClient(t.Address, t.Algorithm, t.ASIC), nil
+	},
+	"claymore": func(t TargetConfig) (Miner, error) {
+		miner, err := NewClaymoreClient(t)
+		if err != nil {
+			return nil, err
+		}
+		return miner, nil
+	},
+	"dstm": func(t TargetConfig) (Miner, error) {
+		return NewDSTMClient(t.Address), nil
+	},
+	"ethminer": func(t TargetConfig) (Miner, error) {
+		return NewEthminerClient(t.Address), nil
+	},
+	"ewbf": func(t TargetConfig) (Miner, error) {
+		return NewEWBFClient(t.Address), nil
+	},
+	"excavator": func(t TargetConfig) (Miner, error) {
+		return NewExcavatorClient(t.Address), nil
+	},
+	"gminer": func(t TargetConfig) (Miner, error) {
+		return NewGMinerClient(t.Address), nil
+	},
+	"lolminer": func(t TargetConfig) (Miner, error) {
+		return NewLolMinerClient(t.Address), nil
+	},
+	"miniz": func(t TargetConfig) (Miner, error) {
+		return NewMiniZClient(t.Address), nil
+	},
+	"nbminer": func(t TargetConfig) (Miner, error) {
+		return NewNBMinerClient(t.Address), nil
+	},
+	"srbminer": func(t TargetConfig) (Miner, error) {
+		return NewSRBMinerClient(t.Address), nil
+	},
+	"trex": func(t TargetConfig) (Miner, error) {
+		return NewTRexClient(t.Address), nil
+	},
+	"xmrig": func(t TargetConfig) (Miner, error) {
+		return NewXMRigClient(t.Address, t.Token), nil
+	},
+	"xmrstak": func(t TargetConfig) (Miner, error) {
+		return NewXMRStakClient(t.Address, t.Algorithm), nil
+	},
+}
+
+func LoadConfig(filename string) (*Config, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(content, config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %s", filename, err)
+	}
+
+	return config, nil
+}
+
+func NewMiner(t TargetConfig) (Miner, error) {
+	factory, ok := minerTypes[t.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown miner type %q", t.Type)
+	}
+
+	if t.Address == "" {
+		return nil, fmt.Errorf("miner type %q requires an address", t.Type)
+	}
+
+	return factory(t)
+}
+
+// reservedLabels are the label names of the exporter's own metrics, which
+// target labels must not override.
+var reservedLabels = map[string]bool{
+	"name": true, "version": true, "algorithm": true, "gpu": true,
+	"status": true, "file": true, "sha256": true, "url": true, "user": true,
+	"type": true, "window": true, "card": true, "bios": true, "driver": true,
+	"vendor": true, "bus": true, "board": true, "sensor": true, "fan": true,
+	"reason": true,
+}
+
+// NewExporters builds one Exporter per target. Targets without a name are
+// named after their miner, so two targets of the same type need explicit
+// names to keep their series apart. Labels missing on some targets are set
+// to "" on those, as all series of a metric must share their label names.
+func NewExporters(targets []TargetConfig) ([]*Exporter, error) {
+	miners := []Miner{}
+	names := []string{}
+	seen := map[string]bool{}
+	keys := map[string]bool{}
+
+	for _, t := range targets {
+		miner, err := NewMiner(t)
+		if err != nil {
+			return nil, err
+		}
+
+		name := t.Name
+		if name == "" {
+			name = miner.Name()
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate target name %q, set a unique name for each target", name)
+		}
+		seen[name] = true
+
+		for label := range t.Labels {
+			if !model.LabelName(label).IsValid() || reservedLabels[label] {
+				return nil, fmt.Errorf("target %q: invalid label name %q", name, label)
+			}
+			keys[label] = true
+		}
+
+		miners = append(miners, miner)
+		names = append(names, name)
+	}
+
+	exporters := []*Exporter{}
+	for i, t := range targets {
+		labels := map[string]string{}
+		for key := range keys {
+			labels[key] = t.Labels[key]
+		}
+		exporters = append(exporters, NewExporter(miners[i], names[i], labels))
+	}
+
+	return exporters, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExporters(t *testing.T) {
+	exporters, err := NewExporters([]TargetConfig{
+		{Type: "ccminer", Address: "10.0.3.17:4068", Name: "rig1"},
+		{Type: "ccminer", Address: "10.0.3.18:4068", Name: "rig2", Labels: map[string]string{"room": "basement"}},
+		{Type: "dstm", Address: "10.0.3.19:2222"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(exporters))
+}
+
+func TestNewExportersDuplicateName(t *testing.T) {
+	_, err := NewExporters([]TargetConfig{
+		{Type: "ccminer", Address: "10.0.3.17:4068"},
+		{Type: "ccminer", Address: "10.0.3.18:4068"},
+	})
+
+	assert.NotNil(t, err)
+}
+
+func TestNewExportersInvalid(t *testing.T) {
+	_, err := NewExporters([]TargetConfig{{Type: "bogus", Address: "10.0.3.17:4068"}})
+	assert.NotNil(t, err)
+
+	_, err = NewExporters([]TargetConfig{{Type: "ccminer"}})
+	assert.NotNil(t, err)
+
+	_, err = NewExporters([]TargetConfig{{Type: "claymore", Address: "10.0.3.17:3333", Variant: "bogus"}})
+	assert.NotNil(t, err)
+
+	_, err = NewExporters([]TargetConfig{{Type: "ccminer", Address: "10.0.3.17:4068", Labels: map[string]string{"name": "rig"}}})
+	assert.NotNil(t, err)
+
+	_, err = NewExporters([]TargetConfig{{Type: "ccminer", Address: "10.0.3.17:4068", Labels: map[string]string{"gpu": "0"}}})
+	assert.NotNil(t, err)
+}
+
+func TestNewExportersMixedLabels(t *testing.T) {
+	exporters, err := NewExporters([]TargetConfig{
+		{Type: "ccminer", Address: "127.0.0.1:1", Name: "rig1", Labels: map[string]string{"rack": "a"}},
+		{Type: "ccminer", Address: "127.0.0.1:1", Name: "rig2"},
+	})
+	assert.Nil(t, err)
+
+	recorder := httptest.NewRecorder()
+	NewMetricsHandler(exporters, time.Second).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `miner_up{name="rig1",rack="a"} 0`)
+	assert.Contains(t, recorder.Body.String(), `miner_up{name="rig2",rack=""} 0`)
+}
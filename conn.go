@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// dial connects to a miner API and applies the deadline of ctx to the
+// connection, so that neither connecting nor any later read or write can
+// outlive the scrape. The caller is responsible for closing the connection.
+func dial(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// callJSON sends a single newline-delimited JSON request to a miner API and
+// decodes the first line of the reply.
+func callJSON(ctx context.Context, address string, request interface{}, reply interface{}) error {
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	command, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	if _, err = conn.Write(append(command, '\n')); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bufio.NewReader(conn))
+	scanner.Buffer(nil, 1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		// The miner hung up without sending a single byte.
+		return &net.OpError{Op: "read", Net: "tcp", Addr: conn.RemoteAddr(), Err: io.ErrUnexpectedEOF}
+	}
+
+	return json.Unmarshal(scanner.Bytes(), reply)
+}
+
+// httpError is returned for miner HTTP APIs answering with a non-2xx status.
+type httpError struct {
+	status int
+	url    string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.url, e.status, http.StatusText(e.status))
+}
+
+// authError is returned when a miner API refuses our credentials.
+type authError struct {
+	message string
+}
+
+func (e *authError) Error() string {
+	return "authentication failed: " + e.message
+}
+
+// getJSON fetches url and decodes the JSON reply. A non-empty token is sent
+// as bearer token.
+func getJSON(ctx context.Context, url string, token string, reply interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return &httpError{response.StatusCode, url}
+	}
+
+	return json.NewDecoder(response.Body).Decode(reply)
+}
+
+// baseURL accepts a miner address with or without scheme.
+func baseURL(address string) string {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return strings.TrimSuffix(address, "/")
+}
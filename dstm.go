@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
-	"net"
+	"fmt"
+	"io"
 )
 
 type DSTMClient struct {
@@ -11,7 +13,7 @@ type DSTMClient struct {
 }
 
 type DSTMAPI interface {
-	GetStat() (*getStat, error)
+	GetStat(ctx context.Context) (*getStat, error)
 }
 
 type getStat struct {
@@ -34,7 +36,7 @@ type result struct {
 	SolPerWatt     float64 `json:"sol_pw"`
 	AvgSolPerWatt  float64 `json:"avg_sol_pw"`
 	PowerUsage     float64 `json:"power_usage"`
-	AvgPowerUsage  float64 `json:"avg_power_uasge"`
+	AvgPowerUsage  float64 `json:"avg_power_usage"`
 	AcceptedShares int     `json:"accepted_shares"`
 	RejectedShares int     `json:"rejected_shares"`
 	Latency        int     `json:"latency"`
@@ -44,11 +46,13 @@ type dstmAPIClient struct {
 	address string
 }
 
-func (c dstmAPIClient) GetStat() (*getStat, error) {
-	conn, err := net.Dial("tcp", c.address)
+func (c dstmAPIClient) GetStat(ctx context.Context) (*getStat, error) {
+	conn, err := dial(ctx, "tcp", c.address)
 	if err != nil {
 		return nil, err
 	}
+	defer conn.Close()
+
 	result := getStat{}
 	command := "{\"id\": 1, \"method\": \"getstat\"}"
 	_, err = conn.Write([]byte(command))
@@ -57,11 +61,16 @@ func (c dstmAPIClient) GetStat() (*getStat, error) {
 	}
 
 	scanner := bufio.NewScanner(bufio.NewReader(conn))
-	if scanner.Scan(); scanner.Err() != nil {
-		return nil, err
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
 	}
 
-	json.Unmarshal([]byte(scanner.Text()), &result)
+	if err := json.Unmarshal([]byte(scanner.Text()), &result); err != nil {
+		return nil, err
+	}
 
 	return &result, nil
 }
@@ -74,22 +83,41 @@ func (c *DSTMClient) Name() string {
 	return "dstm"
 }
 
-func (c *DSTMClient) Collect() (*Metrics, error) {
-	stats, err := c.api.GetStat()
+func (c *DSTMClient) Collect(ctx context.Context) (*Metrics, error) {
+	stats, err := c.api.GetStat(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	byGPU := []float64{}
+	average := []*float64{}
+	efficiency := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
 	accepted := 0
 	rejected := 0
 	total := 0.0
 	for _, gpu := range stats.Result {
 		rate := gpu.SolPerSecond
 		byGPU = append(byGPU, rate)
+		average = append(average, reading(gpu.AvgSolPerSec))
+		efficiency = append(efficiency, gpu.SolPerWatt)
 		total = total + rate
 		accepted = accepted + gpu.AcceptedShares
 		rejected = rejected + gpu.RejectedShares
+
+		sharesByGPU = append(sharesByGPU, Shares{
+			Accepted: float64(gpu.AcceptedShares),
+			Rejected: float64(gpu.RejectedShares),
+		})
+
+		gpus = append(gpus, GPU{
+			Temperature:  reading(float64(gpu.Temperature)),
+			Power:        reading(gpu.PowerUsage),
+			AveragePower: reading(gpu.AvgPowerUsage),
+			// DSTM reports the latency in milliseconds.
+			Latency: reading(float64(gpu.Latency) / 1000),
+		})
 	}
 
 	return &Metrics{
@@ -104,10 +132,19 @@ func (c *DSTMClient) Collect() (*Metrics, error) {
 					Stale:    0.0,
 				},
 				Rates: Rates{
-					Total: total,
-					ByGPU: byGPU,
+					Total:           total,
+					ByGPU:           byGPU,
+					AverageByGPU:    average,
+					EfficiencyByGPU: efficiency,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool: Pool{
+					URL:       fmt.Sprintf("%s:%d", stats.Server, stats.Port),
+					User:      stats.User,
+					Connected: reading(float64(stats.Contime)),
 				},
 			},
 		},
+		GPUs: gpus,
 	}, nil
 }
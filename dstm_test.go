@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -105,7 +106,7 @@ type MockedDSTMAPI struct {
 	mock.Mock
 }
 
-func (m *MockedDSTMAPI) GetStat() (*getStat, error) {
+func (m *MockedDSTMAPI) GetStat(ctx context.Context) (*getStat, error) {
 	args := m.Called()
 
 	var result getStat
@@ -120,7 +121,7 @@ func TestDSTMCollect(t *testing.T) {
 	mockAPI.On("GetStat").Return(GETSTAT, nil)
 
 	miner := &DSTMClient{mockAPI}
-	metrics, _ := miner.Collect()
+	metrics, _ := miner.Collect(context.Background())
 
 	assert.Equal(t, "dstm", miner.Name())
 	assert.Equal(t, "0.5.8", metrics.Version)
@@ -136,4 +137,15 @@ func TestDSTMCollect(t *testing.T) {
 	assert.Equal(t, 421.73, metrics.Algorithms[0].Rates.ByGPU[4])
 	assert.Equal(t, 416.42, metrics.Algorithms[0].Rates.ByGPU[5])
 	assert.Equal(t, 2543.84, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, 430.71, *metrics.Algorithms[0].Rates.AverageByGPU[0])
+	assert.Equal(t, 4.32, metrics.Algorithms[0].Rates.EfficiencyByGPU[0])
+	assert.Equal(t, 59.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 97.86, *metrics.GPUs[0].Power)
+	assert.Equal(t, 99.68, *metrics.GPUs[0].AveragePower)
+	assert.Equal(t, 0.287, *metrics.GPUs[0].Latency)
+	assert.Equal(t, 3.0, metrics.Algorithms[0].SharesByGPU[4].Accepted)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].SharesByGPU[4].Rejected)
+	assert.Equal(t, "europe.equihash-hub.miningpoolhub.com:17023", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "BugRoger.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, 236.0, *metrics.Algorithms[0].Pool.Connected)
 }
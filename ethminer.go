@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type EthminerClient struct {
+	api EthminerAPI
+}
+
+type EthminerAPI interface {
+	GetStatDetail(ctx context.Context) (*statDetail, error)
+}
+
+type statDetail struct {
+	Connection struct {
+		Connected bool   `json:"connected"`
+		Switches  int    `json:"switches"`
+		URI       string `json:"uri"`
+	} `json:"connection"`
+	Devices []ethminerDevice `json:"devices"`
+	Host    struct {
+		Name    string `json:"name"`
+		Runtime int    `json:"runtime"`
+		Version string `json:"version"`
+	} `json:"host"`
+	Mining struct {
+		Difficulty float64 `json:"difficulty"`
+		Epoch      int     `json:"epoch"`
+		Hashrate   string  `json:"hashrate"`
+		Shares     []int   `json:"shares"`
+	} `json:"mining"`
+}
+
+type ethminerDevice struct {
+	Index    int    `json:"_index"`
+	Mode     string `json:"_mode"`
+	Hardware struct {
+		Name    string    `json:"name"`
+		PCI     string    `json:"pci"`
+		Sensors []float64 `json:"sensors"`
+		Type    string    `json:"type"`
+	} `json:"hardware"`
+	Mining struct {
+		Hashrate string `json:"hashrate"`
+		Paused   bool   `json:"paused"`
+		Shares   []int  `json:"shares"`
+	} `json:"mining"`
+}
+
+type ethminerRequest struct {
+	ID      int    `json:"id"`
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+}
+
+type ethminerResponse struct {
+	ID     int         `json:"id"`
+	Result *statDetail `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type ethminerAPIClient struct {
+	address string
+}
+
+func (c ethminerAPIClient) GetStatDetail(ctx context.Context) (*statDetail, error) {
+	response := ethminerResponse{}
+	request := ethminerRequest{1, "2.0", "miner_getstatdetail"}
+	if err := callJSON(ctx, c.address, request, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, errors.New(response.Error.Message)
+	}
+
+	if response.Result == nil {
+		return nil, errors.New("empty reply to miner_getstatdetail")
+	}
+
+	return response.Result, nil
+}
+
+func NewEthminerClient(address string) *EthminerClient {
+	return &EthminerClient{ethminerAPIClient{address}}
+}
+
+func (c *EthminerClient) Name() string {
+	return "ethminer"
+}
+
+func (c *EthminerClient) Collect(ctx context.Context) (*Metrics, error) {
+	stats, err := c.api.GetStatDetail(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byGPU := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
+	for _, device := range stats.Devices {
+		byGPU = append(byGPU, parseHex(device.Mining.Hashrate))
+		sharesByGPU = append(sharesByGPU, ethminerShares(device.Mining.Shares))
+
+		// Sensors are temperature, fan speed and power, as far as the
+		// hardware monitor of ethminer is enabled.
+		gpu := GPU{Card: device.Hardware.Name}
+		sensors := device.Hardware.Sensors
+		if len(sensors) > 0 {
+			gpu.Temperature = reading(sensors[0])
+		}
+		if len(sensors) > 1 {
+			gpu.FanPercent = reading(sensors[1])
+		}
+		if len(sensors) > 2 {
+			gpu.Power = reading(sensors[2])
+		}
+		gpus = append(gpus, gpu)
+	}
+
+	pool := Pool{
+		Up:         boolReading(stats.Connection.Connected),
+		Difficulty: reading(stats.Mining.Difficulty),
+		Switches:   reading(float64(stats.Connection.Switches)),
+	}
+	if uri, err := url.Parse(stats.Connection.URI); err == nil {
+		if uri.User != nil {
+			pool.User = uri.User.Username()
+		}
+		uri.User = nil
+		pool.URL = uri.String()
+	}
+	if len(stats.Mining.Shares) > 3 {
+		pool.LastShare = reading(float64(stats.Mining.Shares[3]))
+	}
+
+	return &Metrics{
+		Version: stats.Host.Version,
+		Uptime:  float64(stats.Host.Runtime),
+		Algorithms: []Algorithm{
+			{
+				Name:   "ethash",
+				Shares: ethminerShares(stats.Mining.Shares),
+				Rates: Rates{
+					Total: parseHex(stats.Mining.Hashrate),
+					ByGPU: byGPU,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool:        pool,
+			},
+		},
+		GPUs: gpus,
+	}, nil
+}
+
+// ethminerShares maps the accepted, rejected and failed share counts.
+func ethminerShares(shares []int) Shares {
+	result := Shares{}
+	if len(shares) > 2 {
+		result.Accepted = float64(shares[0])
+		result.Rejected = float64(shares[1])
+		result.Invalid = float64(shares[2])
+	}
+	return result
+}
+
+func parseHex(input string) float64 {
+	value, _ := strconv.ParseUint(strings.TrimPrefix(input, "0x"), 16, 64)
+	return float64(value)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	GETSTATDETAIL = `{
+   "connection":{
+      "connected":true,
+      "switches":1,
+      "uri":"stratum1+tcp://0x4cbd2bee2b7b8f8fc3e6b5a6b4fd2d4f0f0b1c2d.wupse@eu1.ethermine.org:4444"
+   },
+   "devices":[
+      {
+         "_index":0,
+         "_mode":"CUDA",
+         "hardware":{
+            "name":"GeForce GTX 1070 7.93 GB",
+            "pci":"01:00.0",
+            "sensors":[61,55,121],
+            "type":"GPU"
+         },
+         "mining":{
+            "hashrate":"0x01c9c380",
+            "pause_reason":null,
+            "paused":false,
+            "segment":["0x9c5a5b3cfcd4ba21","0x9c5a5b3dfcd4ba21"],
+            "shares":[31,1,0,12]
+         }
+      },
+      {
+         "_index":1,
+         "_mode":"CUDA",
+         "hardware":{
+            "name":"GeForce GTX 1070 7.93 GB",
+            "pci":"02:00.0",
+            "sensors":[58,50,118],
+            "type":"GPU"
+         },
+         "mining":{
+            "hashrate":"0x01c4b4a0",
+            "pause_reason":null,
+            "paused":false,
+            "segment":["0x9c5a5b3dfcd4ba21","0x9c5a5b3efcd4ba21"],
+            "shares":[28,0,1,40]
+         }
+      }
+   ],
+   "host":{
+      "name":"wupse",
+      "runtime":3605,
+      "version":"ethminer-0.18.0"
+   },
+   "mining":{
+      "difficulty":4000000000,
+      "epoch":312,
+      "epoch_changes":1,
+      "hashrate":"0x038e7820",
+      "shares":[59,1,1,12]
+   },
+   "monitors":null
+}`
+)
+
+type MockedEthminerAPI struct {
+	mock.Mock
+}
+
+func (m *MockedEthminerAPI) GetStatDetail(ctx context.Context) (*statDetail, error) {
+	args := m.Called()
+
+	var result statDetail
+	json.Unmarshal([]byte(args.String(0)), &result)
+
+	return &result, args.Error(1)
+}
+
+func TestEthminerCollect(t *testing.T) {
+	mockAPI := new(MockedEthminerAPI)
+
+	mockAPI.On("GetStatDetail").Return(GETSTATDETAIL, nil)
+
+	miner := &EthminerClient{mockAPI}
+	metrics, _ := miner.Collect(context.Background())
+
+	assert.Equal(t, "ethminer", miner.Name())
+	assert.Equal(t, "ethminer-0.18.0", metrics.Version)
+	assert.Equal(t, 3605.0, metrics.Uptime)
+	assert.Equal(t, "ethash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 1.0, *metrics.Algorithms[0].Pool.Up)
+	assert.Equal(t, 59.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Invalid)
+	assert.Equal(t, 59668512.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, 30000000.0, metrics.Algorithms[0].Rates.ByGPU[0])
+	assert.Equal(t, 29668512.0, metrics.Algorithms[0].Rates.ByGPU[1])
+	assert.Equal(t, 28.0, metrics.Algorithms[0].SharesByGPU[1].Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].SharesByGPU[1].Invalid)
+	assert.Equal(t, "stratum1+tcp://eu1.ethermine.org:4444", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "0x4cbd2bee2b7b8f8fc3e6b5a6b4fd2d4f0f0b1c2d.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, 4000000000.0, *metrics.Algorithms[0].Pool.Difficulty)
+	assert.Equal(t, 12.0, *metrics.Algorithms[0].Pool.LastShare)
+	assert.Equal(t, 1.0, *metrics.Algorithms[0].Pool.Switches)
+	assert.Equal(t, "GeForce GTX 1070 7.93 GB", metrics.GPUs[0].Card)
+	assert.Equal(t, 61.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 55.0, *metrics.GPUs[0].FanPercent)
+	assert.Equal(t, 118.0, *metrics.GPUs[1].Power)
+}
+
+func TestEthminerAPIClient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		request, _ := bufio.NewReader(conn).ReadString('\n')
+		if strings.Contains(request, `"method":"miner_getstatdetail"`) {
+			conn.Write([]byte(`{"id":1,"jsonrpc":"2.0","result":` + strings.Replace(GETSTATDETAIL, "\n", "", -1) + "}\n"))
+		}
+	}()
+
+	stats, err := ethminerAPIClient{listener.Addr().String()}.GetStatDetail(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ethminer-0.18.0", stats.Host.Version)
+	assert.Equal(t, 2, len(stats.Devices))
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// EWBFClient reads EWBF's Zcash miner and miniZ, which answer the same
+// getstat request with slightly different replies.
+type EWBFClient struct {
+	api  EWBFAPI
+	name string
+}
+
+type EWBFAPI interface {
+	GetStat(ctx context.Context) (*ewbfGetStat, error)
+}
+
+type ewbfGetStat struct {
+	ID               int          `json:"id"`
+	Method           string       `json:"method"`
+	Error            *string      `json:"error"`
+	StartTime        int64        `json:"start_time"`
+	CurrentServer    string       `json:"current_server"`
+	AvailableServers int          `json:"available_servers"`
+	ServerStatus     int          `json:"server_status"`
+	Result           []ewbfResult `json:"result"`
+
+	// Only sent by miniZ.
+	Version string `json:"version"`
+	Uptime  *int   `json:"uptime"`
+	User    string `json:"user"`
+	Algo    string `json:"algo"`
+}
+
+type ewbfResult struct {
+	GpuID          int     `json:"gpuid"`
+	CudaID         int     `json:"cudaid"`
+	BusID          string  `json:"busid"`
+	Name           string  `json:"name"`
+	GpuStatus      int     `json:"gpu_status"`
+	Solver         int     `json:"solver"`
+	Temperature    float64 `json:"temperature"`
+	GpuPowerUsage  float64 `json:"gpu_power_usage"`
+	SpeedSps       float64 `json:"speed_sps"`
+	AcceptedShares int     `json:"accepted_shares"`
+	RejectedShares int     `json:"rejected_shares"`
+	StartTime      int64   `json:"start_time"`
+}
+
+type ewbfAPIClient struct {
+	address string
+}
+
+func (c ewbfAPIClient) GetStat(ctx context.Context) (*ewbfGetStat, error) {
+	result := ewbfGetStat{}
+	request := map[string]interface{}{"id": 1, "method": "getstat"}
+	if err := callJSON(ctx, c.address, request, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Error != nil {
+		return nil, errors.New(*result.Error)
+	}
+
+	return &result, nil
+}
+
+func NewEWBFClient(address string) *EWBFClient {
+	return &EWBFClient{ewbfAPIClient{address}, "ewbf"}
+}
+
+func NewMiniZClient(address string) *EWBFClient {
+	return &EWBFClient{ewbfAPIClient{address}, "miniz"}
+}
+
+func (c *EWBFClient) Name() string {
+	return c.name
+}
+
+func (c *EWBFClient) Collect(ctx context.Context) (*Metrics, error) {
+	stats, err := c.api.GetStat(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byGPU := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
+	accepted := 0
+	rejected := 0
+	total := 0.0
+	efficiency := []float64{}
+	for _, gpu := range stats.Result {
+		rate := gpu.SpeedSps
+		byGPU = append(byGPU, rate)
+		total = total + rate
+		accepted = accepted + gpu.AcceptedShares
+		rejected = rejected + gpu.RejectedShares
+
+		sharesByGPU = append(sharesByGPU, Shares{
+			Accepted: float64(gpu.AcceptedShares),
+			Rejected: float64(gpu.RejectedShares),
+		})
+
+		perWatt := 0.0
+		if gpu.GpuPowerUsage > 0 {
+			perWatt = rate / gpu.GpuPowerUsage
+		}
+		efficiency = append(efficiency, perWatt)
+
+		gpus = append(gpus, GPU{
+			Card:        gpu.Name,
+			Temperature: reading(gpu.Temperature),
+			Power:       reading(gpu.GpuPowerUsage),
+		})
+	}
+
+	uptime := 0.0
+	if stats.Uptime != nil {
+		uptime = float64(*stats.Uptime)
+	} else if stats.StartTime > 0 {
+		uptime = time.Since(time.Unix(stats.StartTime, 0)).Seconds()
+	}
+
+	algorithm := "equihash"
+	if stats.Algo != "" {
+		algorithm = "equihash" + strings.Replace(stats.Algo, ",", "_", -1)
+	}
+
+	return &Metrics{
+		Version: stats.Version,
+		Uptime:  uptime,
+		Algorithms: []Algorithm{
+			{
+				Name: algorithm,
+				Shares: Shares{
+					Accepted: float64(accepted),
+					Rejected: float64(rejected),
+				},
+				Rates: Rates{
+					Total:           total,
+					ByGPU:           byGPU,
+					EfficiencyByGPU: efficiency,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool: Pool{
+					URL:  stats.CurrentServer,
+					User: stats.User,
+				},
+			},
+		},
+		GPUs: gpus,
+	}, nil
+}
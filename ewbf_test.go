@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	EWBF_GETSTAT = `{
+   "id":1,
+   "method":"getstat",
+   "error":null,
+   "start_time":1515360000,
+   "current_server":"eu1-zcash.flypool.org:3333",
+   "available_servers":1,
+   "server_status":2,
+   "result":[
+      {
+         "gpuid":0,
+         "cudaid":0,
+         "busid":"0000:01:00.0",
+         "name":"GeForce GTX 1080 Ti",
+         "gpu_status":2,
+         "solver":0,
+         "temperature":64,
+         "gpu_power_usage":220,
+         "speed_sps":726,
+         "accepted_shares":120,
+         "rejected_shares":2,
+         "start_time":1515360000
+      },
+      {
+         "gpuid":1,
+         "cudaid":1,
+         "busid":"0000:02:00.0",
+         "name":"GeForce GTX 1070",
+         "gpu_status":2,
+         "solver":0,
+         "temperature":59,
+         "gpu_power_usage":0,
+         "speed_sps":450,
+         "accepted_shares":75,
+         "rejected_shares":0,
+         "start_time":1515360000
+      }
+   ]
+}`
+
+	MINIZ_GETSTAT = `{
+   "id":0,
+   "method":"getstat",
+   "error":null,
+   "uptime":7260,
+   "version":"1.5q2",
+   "algo":"144,5",
+   "current_server":"equihash144.eu.nicehash.com:3369",
+   "user":"BugRoger.wupse",
+   "server_status":2,
+   "result":[
+      {
+         "gpuid":0,
+         "cudaid":0,
+         "busid":"0000:01:00.0",
+         "name":"GeForce GTX 1080",
+         "gpu_status":2,
+         "solver":0,
+         "temperature":61,
+         "gpu_power_usage":160,
+         "speed_sps":48,
+         "accepted_shares":31,
+         "rejected_shares":1,
+         "start_time":1536596380
+      }
+   ]
+}`
+)
+
+type MockedEWBFAPI struct {
+	mock.Mock
+}
+
+func (m *MockedEWBFAPI) GetStat(ctx context.Context) (*ewbfGetStat, error) {
+	args := m.Called()
+
+	var result ewbfGetStat
+	json.Unmarshal([]byte(args.String(0)), &result)
+
+	return &result, args.Error(1)
+}
+
+func TestEWBFCollect(t *testing.T) {
+	mockAPI := new(MockedEWBFAPI)
+
+	mockAPI.On("GetStat").Return(EWBF_GETSTAT, nil)
+
+	miner := &EWBFClient{mockAPI, "ewbf"}
+	metrics, _ := miner.Collect(context.Background())
+
+	assert.Equal(t, "ewbf", miner.Name())
+	assert.Equal(t, "", metrics.Version)
+	assert.True(t, metrics.Uptime > 0)
+	assert.Equal(t, "equihash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 195.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 1176.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{726, 450}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, 3.3, metrics.Algorithms[0].Rates.EfficiencyByGPU[0])
+	assert.Equal(t, 0.0, metrics.Algorithms[0].Rates.EfficiencyByGPU[1])
+	assert.Equal(t, 120.0, metrics.Algorithms[0].SharesByGPU[0].Accepted)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].SharesByGPU[0].Rejected)
+	assert.Equal(t, "GeForce GTX 1080 Ti", metrics.GPUs[0].Card)
+	assert.Equal(t, 64.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 220.0, *metrics.GPUs[0].Power)
+	assert.Equal(t, "eu1-zcash.flypool.org:3333", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "", metrics.Algorithms[0].Pool.User)
+}
+
+func TestMiniZCollect(t *testing.T) {
+	mockAPI := new(MockedEWBFAPI)
+
+	mockAPI.On("GetStat").Return(MINIZ_GETSTAT, nil)
+
+	miner := &EWBFClient{mockAPI, "miniz"}
+	metrics, _ := miner.Collect(context.Background())
+
+	assert.Equal(t, "miniz", miner.Name())
+	assert.Equal(t, "1.5q2", metrics.Version)
+	assert.Equal(t, 7260.0, metrics.Uptime)
+	assert.Equal(t, "equihash144_5", metrics.Algorithms[0].Name)
+	assert.Equal(t, 31.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 48.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, 0.3, metrics.Algorithms[0].Rates.EfficiencyByGPU[0])
+	assert.Equal(t, 61.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 160.0, *metrics.GPUs[0].Power)
+	assert.Equal(t, "equihash144.eu.nicehash.com:3369", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "BugRoger.wupse", metrics.Algorithms[0].Pool.User)
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// ExcavatorClient reads NiceHash Excavator, which can run a different
+// algorithm on each GPU. Every worker runs one or more algorithms on a single
+// device, so its speeds become the per GPU rates of those algorithms.
+type ExcavatorClient struct {
+	api ExcavatorAPI
+}
+
+type ExcavatorAPI interface {
+	Info(ctx context.Context) (*excavatorInfo, error)
+	AlgorithmList(ctx context.Context) (*excavatorAlgorithms, error)
+	WorkerList(ctx context.Context) (*excavatorWorkers, error)
+	DeviceList(ctx context.Context) (*excavatorDevices, error)
+	SubscribeInfo(ctx context.Context) (*excavatorSubscription, error)
+}
+
+// excavatorReply is embedded in every reply, carrying the error of the call.
+type excavatorReply struct {
+	ID    int     `json:"id"`
+	Error *string `json:"error"`
+}
+
+func (r *excavatorReply) err() error {
+	if r.Error != nil {
+		return errors.New(*r.Error)
+	}
+	return nil
+}
+
+type excavatorInfo struct {
+	excavatorReply
+	Version string  `json:"version"`
+	Uptime  float64 `json:"uptime"`
+}
+
+type excavatorAlgorithms struct {
+	excavatorReply
+	Algorithms []struct {
+		ID                   int      `json:"algorithm_id"`
+		Name                 string   `json:"name"`
+		Speed                float64  `json:"speed"`
+		AcceptedShares       float64  `json:"accepted_shares"`
+		RejectedShares       float64  `json:"rejected_shares"`
+		CurrentJobDifficulty *float64 `json:"current_job_difficulty"`
+
+		// Only sent by versions before 1.5, which subscribe per algorithm.
+		Connected *bool  `json:"connected"`
+		Address   string `json:"address"`
+		Login     string `json:"login"`
+	} `json:"algorithms"`
+}
+
+type excavatorWorkers struct {
+	excavatorReply
+	Workers []struct {
+		WorkerID   int `json:"worker_id"`
+		DeviceID   int `json:"device_id"`
+		Algorithms []struct {
+			ID    int     `json:"id"`
+			Name  string  `json:"name"`
+			Speed float64 `json:"speed"`
+		} `json:"algorithms"`
+	} `json:"workers"`
+}
+
+type excavatorDevices struct {
+	excavatorReply
+	Devices []struct {
+		DeviceID             int      `json:"device_id"`
+		Name                 string   `json:"name"`
+		GPUTemp              *float64 `json:"gpu_temp"`
+		GPUFanSpeed          *float64 `json:"gpu_fan_speed"`
+		GPUFanSpeedRPM       *float64 `json:"gpu_fan_speed_rpm"`
+		GPUPowerUsage        *float64 `json:"gpu_power_usage"`
+		GPUPowerLimitCurrent *float64 `json:"gpu_power_limit_current"`
+		GPUClockCore         *float64 `json:"gpu_clock_core"`
+		GPUClockMemory       *float64 `json:"gpu_clock_memory"`
+	} `json:"devices"`
+}
+
+type excavatorSubscription struct {
+	excavatorReply
+	Connected bool   `json:"connected"`
+	Address   string `json:"address"`
+	Login     string `json:"login"`
+}
+
+type excavatorAPIClient struct {
+	address string
+}
+
+type excavatorResult interface {
+	err() error
+}
+
+func (c excavatorAPIClient) call(ctx context.Context, method string, reply excavatorResult) error {
+	request := map[string]interface{}{"id": 1, "method": method, "params": []string{}}
+	if err := callJSON(ctx, c.address, request, reply); err != nil {
+		return err
+	}
+	return reply.err()
+}
+
+func (c excavatorAPIClient) Info(ctx context.Context) (*excavatorInfo, error) {
+	result := excavatorInfo{}
+	return &result, c.call(ctx, "info", &result)
+}
+
+func (c excavatorAPIClient) AlgorithmList(ctx context.Context) (*excavatorAlgorithms, error) {
+	result := excavatorAlgorithms{}
+	return &result, c.call(ctx, "algorithm.list", &result)
+}
+
+func (c excavatorAPIClient) WorkerList(ctx context.Context) (*excavatorWorkers, error) {
+	result := excavatorWorkers{}
+	return &result, c.call(ctx, "worker.list", &result)
+}
+
+func (c excavatorAPIClient) DeviceList(ctx context.Context) (*excavatorDevices, error) {
+	result := excavatorDevices{}
+	return &result, c.call(ctx, "device.list", &result)
+}
+
+func (c excavatorAPIClient) SubscribeInfo(ctx context.Context) (*excavatorSubscription, error) {
+	result := excavatorSubscription{}
+	return &result, c.call(ctx, "subscribe.info", &result)
+}
+
+func NewExcavatorClient(address string) *ExcavatorClient {
+	return &ExcavatorClient{excavatorAPIClient{address}}
+}
+
+func (c *ExcavatorClient) Name() string {
+	return "excavator"
+}
+
+func (c *ExcavatorClient) Collect(ctx context.Context) (*Metrics, error) {
+	info, err := c.api.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithms, err := c.api.AlgorithmList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workers, err := c.api.WorkerList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := c.api.DeviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Versions before 1.5 know no subscribe.info, but report the pool of
+	// each algorithm instead.
+	subscription, err := c.api.SubscribeInfo(ctx)
+	if err != nil {
+		if !subscribedPerAlgorithm(algorithms) {
+			return nil, err
+		}
+		subscription = &excavatorSubscription{}
+	}
+
+	gpus := []GPU{}
+	index := map[int]int{}
+	for i, device := range devices.Devices {
+		index[device.DeviceID] = i
+		gpus = append(gpus, GPU{
+			Card:            device.Name,
+			Temperature:     device.GPUTemp,
+			FanPercent:      device.GPUFanSpeed,
+			FanRPM:          device.GPUFanSpeedRPM,
+			Power:           device.GPUPowerUsage,
+			PowerLimitWatts: device.GPUPowerLimitCurrent,
+			CoreClock:       device.GPUClockCore,
+			MemoryClock:     device.GPUClockMemory,
+		})
+	}
+
+	// GPUs not running an algorithm report a rate of zero for it.
+	byGPU := map[string][]float64{}
+	for _, worker := range workers.Workers {
+		i, ok := index[worker.DeviceID]
+		if !ok {
+			continue
+		}
+		for _, algo := range worker.Algorithms {
+			if byGPU[algo.Name] == nil {
+				byGPU[algo.Name] = make([]float64, len(gpus))
+			}
+			byGPU[algo.Name][i] += algo.Speed
+		}
+	}
+
+	algos := []Algorithm{}
+	for _, algo := range algorithms.Algorithms {
+		pool := Pool{
+			URL:        subscription.Address,
+			User:       subscription.Login,
+			Up:         boolReading(subscription.Connected),
+			Difficulty: algo.CurrentJobDifficulty,
+		}
+		if algo.Connected != nil {
+			pool.URL = algo.Address
+			pool.User = algo.Login
+			pool.Up = boolReading(*algo.Connected)
+		}
+
+		rates := byGPU[algo.Name]
+		if rates == nil {
+			rates = []float64{}
+		}
+
+		algos = append(algos, Algorithm{
+			Name: algo.Name,
+			Shares: Shares{
+				Accepted: algo.AcceptedShares,
+				Rejected: algo.RejectedShares,
+			},
+			Rates: Rates{
+				Total: algo.Speed,
+				ByGPU: rates,
+			},
+			Pool: pool,
+		})
+	}
+
+	return &Metrics{
+		Version:    info.Version,
+		Uptime:     info.Uptime,
+		Algorithms: algos,
+		GPUs:       gpus,
+	}, nil
+}
+
+func subscribedPerAlgorithm(algorithms *excavatorAlgorithms) bool {
+	for _, algo := range algorithms.Algorithms {
+		if algo.Connected == nil {
+			return false
+		}
+	}
+	return len(algorithms.Algorithms) > 0
+}
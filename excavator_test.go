@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	EXCAVATOR_INFO = `{"version":"1.5.13a","build_platform":"Linux","build_number":571,"uptime":5400.5,"id":1,"error":null}`
+
+	EXCAVATOR_ALGORITHMS = `{
+   "algorithms":[
+      {
+         "algorithm_id":0,
+         "name":"daggerhashimoto",
+         "speed":61500000.0,
+         "uptime":5390.2,
+         "benchmark":false,
+         "accepted_shares":311,
+         "rejected_shares":2,
+         "got_job":true,
+         "received_jobs":1021,
+         "current_job_difficulty":0.5,
+         "workers":[{"worker_id":0},{"worker_id":1}]
+      },
+      {
+         "algorithm_id":1,
+         "name":"equihash",
+         "speed":480.0,
+         "uptime":5391.0,
+         "benchmark":false,
+         "accepted_shares":57,
+         "rejected_shares":0,
+         "got_job":true,
+         "received_jobs":310,
+         "current_job_difficulty":1024,
+         "workers":[{"worker_id":2}]
+      }
+   ],
+   "id":1,
+   "error":null
+}`
+
+	EXCAVATOR_WORKERS = `{
+   "workers":[
+      {"worker_id":0, "device_id":0, "params":[], "algorithms":[{"id":0, "name":"daggerhashimoto", "speed":30500000.0}]},
+      {"worker_id":1, "device_id":1, "params":[], "algorithms":[{"id":0, "name":"daggerhashimoto", "speed":31000000.0}]},
+      {"worker_id":2, "device_id":2, "params":[], "algorithms":[{"id":1, "name":"equihash", "speed":480.0}]}
+   ],
+   "id":1,
+   "error":null
+}`
+
+	EXCAVATOR_DEVICES = `{
+   "devices":[
+      {"device_id":0, "name":"GeForce GTX 1070", "gpu_temp":62, "gpu_load":100, "gpu_fan_speed":55, "gpu_power_usage":118.5, "gpu_power_limit_current":120},
+      {"device_id":1, "name":"GeForce GTX 1070", "gpu_temp":58, "gpu_load":100, "gpu_fan_speed":50, "gpu_power_usage":116.2, "gpu_power_limit_current":120},
+      {"device_id":2, "name":"GeForce GTX 1080 Ti", "gpu_temp":66, "gpu_load":100, "gpu_fan_speed":70, "gpu_power_usage":215.0, "gpu_power_limit_current":250}
+   ],
+   "id":1,
+   "error":null
+}`
+
+	EXCAVATOR_SUBSCRIPTION = `{"connected":true,"address":"nhmp.eu.nicehash.com:3200","login":"3Mk8W6JpSEfQcoGYNsXU9bUS8yDDtXStpe.wupse","id":1,"error":null}`
+)
+
+type MockedExcavatorAPI struct {
+	mock.Mock
+}
+
+func decodeMocked(args mock.Arguments, result interface{}) error {
+	json.Unmarshal([]byte(args.String(0)), result)
+	return args.Error(1)
+}
+
+func (m *MockedExcavatorAPI) Info(ctx context.Context) (*excavatorInfo, error) {
+	var result excavatorInfo
+	return &result, decodeMocked(m.Called(), &result)
+}
+
+func (m *MockedExcavatorAPI) AlgorithmList(ctx context.Context) (*excavatorAlgorithms, error) {
+	var result excavatorAlgorithms
+	return &result, decodeMocked(m.Called(), &result)
+}
+
+func (m *MockedExcavatorAPI) WorkerList(ctx context.Context) (*excavatorWorkers, error) {
+	var result excavatorWorkers
+	return &result, decodeMocked(m.Called(), &result)
+}
+
+func (m *MockedExcavatorAPI) DeviceList(ctx context.Context) (*excavatorDevices, error) {
+	var result excavatorDevices
+	return &result, decodeMocked(m.Called(), &result)
+}
+
+func (m *MockedExcavatorAPI) SubscribeInfo(ctx context.Context) (*excavatorSubscription, error) {
+	var result excavatorSubscription
+	return &result, decodeMocked(m.Called(), &result)
+}
+
+func TestExcavatorCollect(t *testing.T) {
+	mockAPI := new(MockedExcavatorAPI)
+
+	mockAPI.On("Info").Return(EXCAVATOR_INFO, nil)
+	mockAPI.On("AlgorithmList").Return(EXCAVATOR_ALGORITHMS, nil)
+	mockAPI.On("WorkerList").Return(EXCAVATOR_WORKERS, nil)
+	mockAPI.On("DeviceList").Return(EXCAVATOR_DEVICES, nil)
+	mockAPI.On("SubscribeInfo").Return(EXCAVATOR_SUBSCRIPTION, nil)
+
+	miner := &ExcavatorClient{mockAPI}
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "excavator", miner.Name())
+	assert.Equal(t, "1.5.13a", metrics.Version)
+	assert.Equal(t, 5400.5, metrics.Uptime)
+	assert.Equal(t, 2, len(metrics.Algorithms))
+
+	eth := metrics.Algorithms[0]
+	assert.Equal(t, "daggerhashimoto", eth.Name)
+	assert.Equal(t, 61500000.0, eth.Rates.Total)
+	assert.Equal(t, []float64{30500000, 31000000, 0}, eth.Rates.ByGPU)
+	assert.Equal(t, 311.0, eth.Shares.Accepted)
+	assert.Equal(t, 2.0, eth.Shares.Rejected)
+	assert.Equal(t, 0.5, *eth.Pool.Difficulty)
+	assert.Equal(t, "nhmp.eu.nicehash.com:3200", eth.Pool.URL)
+	assert.Equal(t, "3Mk8W6JpSEfQcoGYNsXU9bUS8yDDtXStpe.wupse", eth.Pool.User)
+	assert.Equal(t, 1.0, *eth.Pool.Up)
+
+	equihash := metrics.Algorithms[1]
+	assert.Equal(t, "equihash", equihash.Name)
+	assert.Equal(t, []float64{0, 0, 480}, equihash.Rates.ByGPU)
+	assert.Equal(t, 57.0, equihash.Shares.Accepted)
+
+	assert.Equal(t, 3, len(metrics.GPUs))
+	assert.Equal(t, "GeForce GTX 1080 Ti", metrics.GPUs[2].Card)
+	assert.Equal(t, 66.0, *metrics.GPUs[2].Temperature)
+	assert.Equal(t, 70.0, *metrics.GPUs[2].FanPercent)
+	assert.Equal(t, 215.0, *metrics.GPUs[2].Power)
+	assert.Equal(t, 250.0, *metrics.GPUs[2].PowerLimitWatts)
+	assert.Nil(t, metrics.GPUs[2].PowerLimit)
+	assert.Nil(t, metrics.GPUs[2].FanRPM)
+}
+
+func TestExcavatorCollectPerAlgorithmSubscription(t *testing.T) {
+	mockAPI := new(MockedExcavatorAPI)
+
+	mockAPI.On("Info").Return(EXCAVATOR_INFO, nil)
+	mockAPI.On("AlgorithmList").Return(`{
+   "algorithms":[
+      {"algorithm_id":0, "name":"equihash", "connected":false, "address":"equihash.eu.nicehash.com:3357", "login":"wallet.wupse", "speed":0}
+   ],
+   "id":1,
+   "error":null
+}`, nil)
+	mockAPI.On("WorkerList").Return(`{"workers":[],"id":1,"error":null}`, nil)
+	mockAPI.On("DeviceList").Return(EXCAVATOR_DEVICES, nil)
+	mockAPI.On("SubscribeInfo").Return(`{"id":1,"error":"Invalid method"}`, errors.New("Invalid method"))
+
+	metrics, err := (&ExcavatorClient{mockAPI}).Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "equihash.eu.nicehash.com:3357", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "wallet.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, 0.0, *metrics.Algorithms[0].Pool.Up)
+	assert.Equal(t, []float64{}, metrics.Algorithms[0].Rates.ByGPU)
+}
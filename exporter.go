@@ -0,0 +1,622 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Exporter struct {
+	miner      Miner
+	name       string
+	up         *prometheus.Desc
+	uptime     *prometheus.Desc
+	memory     *prometheus.Desc
+	info       *prometheus.Desc
+	rates      *prometheus.Desc
+	ratesTotal *prometheus.Desc
+	shares     *prometheus.Desc
+	gpuShares  *prometheus.Desc
+
+	fileInfo        *prometheus.Desc
+	poolInfo        *prometheus.Desc
+	poolUp          *prometheus.Desc
+	poolDifficulty  *prometheus.Desc
+	poolLatency     *prometheus.Desc
+	poolDisconnects *prometheus.Desc
+	poolLastShare   *prometheus.Desc
+	poolConnected   *prometheus.Desc
+	poolSwitches    *prometheus.Desc
+	poolErrors      *prometheus.Desc
+
+	average    *prometheus.Desc
+	efficiency *prometheus.Desc
+	window     *prometheus.Desc
+
+	gpuInfo            *prometheus.Desc
+	gpuTemperature     *prometheus.Desc
+	gpuFanPercent      *prometheus.Desc
+	gpuFanRPM          *prometheus.Desc
+	gpuPower           *prometheus.Desc
+	gpuAveragePower    *prometheus.Desc
+	gpuPowerLimit      *prometheus.Desc
+	gpuPowerLimitWatts *prometheus.Desc
+	gpuCoreClock       *prometheus.Desc
+	gpuMemoryClock     *prometheus.Desc
+	gpuHardwareErrors  *prometheus.Desc
+	gpuLatency         *prometheus.Desc
+
+	boardChips          *prometheus.Desc
+	boardTemperature    *prometheus.Desc
+	boardFrequency      *prometheus.Desc
+	boardRate           *prometheus.Desc
+	boardHardwareErrors *prometheus.Desc
+	fanRPM              *prometheus.Desc
+
+	scrapeDuration *prometheus.Desc
+	scrapeErrors   *prometheus.Desc
+	lastSuccess    *prometheus.Desc
+	snapshotAge    *prometheus.Desc
+
+	mu               sync.Mutex
+	polling          bool
+	last             *snapshot
+	errors           map[string]float64
+	lastSuccessfulAt time.Time
+}
+
+// snapshot is the outcome of collecting the miner once.
+type snapshot struct {
+	metrics  *Metrics
+	err      error
+	duration time.Duration
+	time     time.Time
+}
+
+func NewExporter(miner Miner, name string, labels map[string]string) *Exporter {
+	constLabels := prometheus.Labels{"name": name}
+	for k, v := range labels {
+		constLabels[k] = v
+	}
+
+	return &Exporter{
+		miner:  miner,
+		name:   name,
+		errors: map[string]float64{},
+		up: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "up"),
+			"Could the miner be reached.",
+			nil,
+			constLabels,
+		),
+		uptime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "uptime"),
+			"Number of seconds since the miner started.",
+			nil,
+			constLabels,
+		),
+		memory: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "memory_bytes"),
+			"Memory the miner uses for its own statistics.",
+			nil,
+			constLabels,
+		),
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "info"),
+			"Information about this miner",
+			[]string{"version"},
+			constLabels,
+		),
+		rates: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rates"),
+			"Mining rate by Algorithm and GPU",
+			[]string{"algorithm", "gpu"},
+			constLabels,
+		),
+		shares: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "shares"),
+			"Shares by Algorithm and Status",
+			[]string{"algorithm", "status"},
+			constLabels,
+		),
+		gpuShares: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "shares"),
+			"Shares by Algorithm, GPU and Status",
+			[]string{"algorithm", "gpu", "status"},
+			constLabels,
+		),
+		ratesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "rates", "total"),
+			"Mining rate total by algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		fileInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "file", "info"),
+			"Hash of the content of a configuration file of the miner",
+			[]string{"file", "sha256"},
+			constLabels,
+		),
+		poolInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "info"),
+			"Information about the pool by Algorithm",
+			[]string{"algorithm", "url", "user"},
+			constLabels,
+		),
+		poolUp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "up"),
+			"Whether the miner is subscribed to the pool by Algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		poolDifficulty: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "difficulty"),
+			"Current share difficulty by Algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		poolLatency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "latency_seconds"),
+			"Pool latency by Algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		poolDisconnects: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "disconnects"),
+			"Pool disconnects by Algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		poolLastShare: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "last_share_seconds"),
+			"Seconds since the last share by Algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		poolConnected: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "connected_seconds"),
+			"Seconds connected to the pool by Algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		poolSwitches: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "switches_total"),
+			"Pool switches by Algorithm",
+			[]string{"algorithm"},
+			constLabels,
+		),
+		poolErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "errors"),
+			"Recent errors logged for the pool by Algorithm and type",
+			[]string{"algorithm", "type"},
+			constLabels,
+		),
+		average: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "rates", "average"),
+			"Mining rate averaged by the miner by Algorithm and GPU",
+			[]string{"algorithm", "gpu"},
+			constLabels,
+		),
+		efficiency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "rates", "efficiency"),
+			"Mining rate per watt by Algorithm and GPU",
+			[]string{"algorithm", "gpu"},
+			constLabels,
+		),
+		window: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "rates", "window"),
+			"Mining rate total averaged over a time window by Algorithm",
+			[]string{"algorithm", "window"},
+			constLabels,
+		),
+		gpuInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "info"),
+			"Information about a GPU",
+			[]string{"gpu", "card", "bios", "driver", "vendor", "bus"},
+			constLabels,
+		),
+		gpuTemperature: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "temperature_celsius"),
+			"Temperature by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuFanPercent: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "fan_percent"),
+			"Fan speed in percent by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuFanRPM: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "fan_rpm"),
+			"Fan speed in revolutions per minute by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuPower: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "power_watts"),
+			"Power draw by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuAveragePower: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "power_average_watts"),
+			"Power draw averaged by the miner by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuPowerLimit: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "power_limit"),
+			"Power limit setting by GPU as reported by the miner",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuPowerLimitWatts: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "power_limit_watts"),
+			"Power limit by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuCoreClock: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "core_clock_mhz"),
+			"Core clock by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuMemoryClock: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "memory_clock_mhz"),
+			"Memory clock by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuHardwareErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "hardware_errors"),
+			"Hardware errors by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		gpuLatency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "latency_seconds"),
+			"Share submission latency by GPU",
+			[]string{"gpu"},
+			constLabels,
+		),
+		boardChips: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "board", "chips"),
+			"ASIC chips by hashboard and status",
+			[]string{"board", "status"},
+			constLabels,
+		),
+		boardTemperature: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "board", "temperature_celsius"),
+			"Temperature by hashboard and sensor",
+			[]string{"board", "sensor"},
+			constLabels,
+		),
+		boardFrequency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "board", "frequency_mhz"),
+			"Average chip frequency by hashboard",
+			[]string{"board"},
+			constLabels,
+		),
+		boardRate: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "board", "rate"),
+			"Mining rate by hashboard",
+			[]string{"board"},
+			constLabels,
+		),
+		boardHardwareErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "board", "hardware_errors"),
+			"Hardware errors by hashboard",
+			[]string{"board"},
+			constLabels,
+		),
+		fanRPM: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fan", "rpm"),
+			"Fan speed in revolutions per minute by fan",
+			[]string{"fan"},
+			constLabels,
+		),
+		scrapeDuration: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "exporter", "scrape_duration_seconds"),
+			"Duration of the last collection from the miner",
+			nil,
+			constLabels,
+		),
+		scrapeErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "exporter", "scrape_errors_total"),
+			"Failed collections from the miner by reason",
+			[]string{"reason"},
+			constLabels,
+		),
+		lastSuccess: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "exporter", "last_success_timestamp_seconds"),
+			"Unix time of the last successful collection from the miner",
+			nil,
+			constLabels,
+		),
+		snapshotAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "exporter", "snapshot_age_seconds"),
+			"Age of the polled snapshot served for this miner",
+			nil,
+			constLabels,
+		),
+	}
+}
+
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.up
+	ch <- e.uptime
+	ch <- e.memory
+	ch <- e.info
+	ch <- e.rates
+	ch <- e.ratesTotal
+	ch <- e.shares
+	ch <- e.gpuShares
+	ch <- e.fileInfo
+	ch <- e.poolInfo
+	ch <- e.poolUp
+	ch <- e.poolDifficulty
+	ch <- e.poolLatency
+	ch <- e.poolDisconnects
+	ch <- e.poolLastShare
+	ch <- e.poolConnected
+	ch <- e.poolSwitches
+	ch <- e.poolErrors
+	ch <- e.average
+	ch <- e.efficiency
+	ch <- e.window
+	ch <- e.gpuInfo
+	ch <- e.gpuTemperature
+	ch <- e.gpuFanPercent
+	ch <- e.gpuFanRPM
+	ch <- e.gpuPower
+	ch <- e.gpuAveragePower
+	ch <- e.gpuPowerLimit
+	ch <- e.gpuPowerLimitWatts
+	ch <- e.gpuCoreClock
+	ch <- e.gpuMemoryClock
+	ch <- e.gpuHardwareErrors
+	ch <- e.gpuLatency
+	ch <- e.boardChips
+	ch <- e.boardTemperature
+	ch <- e.boardFrequency
+	ch <- e.boardRate
+	ch <- e.boardHardwareErrors
+	ch <- e.fanRPM
+	ch <- e.scrapeDuration
+	ch <- e.scrapeErrors
+	ch <- e.lastSuccess
+	ch <- e.snapshotAge
+}
+
+func (e *Exporter) Collect(ctx context.Context, ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	polling, last := e.polling, e.last
+	e.mu.Unlock()
+
+	if !polling {
+		last = e.scrape(ctx)
+	}
+
+	if last == nil {
+		ch <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 0)
+		return
+	}
+
+	e.collectScrape(ch, last)
+
+	if polling {
+		ch <- prometheus.MustNewConstMetric(e.snapshotAge, prometheus.GaugeValue, time.Since(last.time).Seconds())
+	}
+
+	if last.err != nil {
+		ch <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 0)
+		return
+	}
+
+	data := last.metrics
+
+	ch <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(e.info, prometheus.GaugeValue, 1, data.Version)
+	ch <- prometheus.MustNewConstMetric(e.uptime, prometheus.CounterValue, data.Uptime)
+	collectReading(ch, e.memory, data.Memory)
+
+	for _, algo := range data.Algorithms {
+		for gpu, r := range algo.Rates.ByGPU {
+			ch <- prometheus.MustNewConstMetric(e.rates, prometheus.GaugeValue, r, algo.Name, fmt.Sprintf("%v", gpu))
+		}
+
+		for gpu, r := range algo.Rates.AverageByGPU {
+			collectReading(ch, e.average, r, algo.Name, fmt.Sprintf("%v", gpu))
+		}
+
+		for gpu, r := range algo.Rates.EfficiencyByGPU {
+			ch <- prometheus.MustNewConstMetric(e.efficiency, prometheus.GaugeValue, r, algo.Name, fmt.Sprintf("%v", gpu))
+		}
+
+		for window, r := range algo.Rates.Windows {
+			ch <- prometheus.MustNewConstMetric(e.window, prometheus.GaugeValue, r, algo.Name, window)
+		}
+
+		if !algo.Rates.Unreported {
+			ch <- prometheus.MustNewConstMetric(e.ratesTotal, prometheus.GaugeValue, algo.Rates.Total, algo.Name)
+		}
+		ch <- prometheus.MustNewConstMetric(e.shares, prometheus.GaugeValue, algo.Shares.Accepted, algo.Name, "accepted")
+		ch <- prometheus.MustNewConstMetric(e.shares, prometheus.GaugeValue, algo.Shares.Rejected, algo.Name, "rejected")
+		ch <- prometheus.MustNewConstMetric(e.shares, prometheus.GaugeValue, algo.Shares.Stale, algo.Name, "stale")
+		ch <- prometheus.MustNewConstMetric(e.shares, prometheus.GaugeValue, algo.Shares.Invalid, algo.Name, "invalid")
+
+		for gpu, shares := range algo.SharesByGPU {
+			index := fmt.Sprintf("%v", gpu)
+			ch <- prometheus.MustNewConstMetric(e.gpuShares, prometheus.GaugeValue, shares.Accepted, algo.Name, index, "accepted")
+			ch <- prometheus.MustNewConstMetric(e.gpuShares, prometheus.GaugeValue, shares.Rejected, algo.Name, index, "rejected")
+			ch <- prometheus.MustNewConstMetric(e.gpuShares, prometheus.GaugeValue, shares.Stale, algo.Name, index, "stale")
+			ch <- prometheus.MustNewConstMetric(e.gpuShares, prometheus.GaugeValue, shares.Invalid, algo.Name, index, "invalid")
+		}
+
+		if algo.Pool.URL != "" {
+			ch <- prometheus.MustNewConstMetric(e.poolInfo, prometheus.GaugeValue, 1, algo.Name, algo.Pool.URL, algo.Pool.User)
+		}
+		collectReading(ch, e.poolUp, algo.Pool.Up, algo.Name)
+		collectReading(ch, e.poolDifficulty, algo.Pool.Difficulty, algo.Name)
+		collectReading(ch, e.poolLatency, algo.Pool.Latency, algo.Name)
+		collectReading(ch, e.poolDisconnects, algo.Pool.Disconnects, algo.Name)
+		collectReading(ch, e.poolLastShare, algo.Pool.LastShare, algo.Name)
+		collectReading(ch, e.poolConnected, algo.Pool.Connected, algo.Name)
+		collectCounter(ch, e.poolSwitches, algo.Pool.Switches, algo.Name)
+		for kind, count := range algo.Pool.Errors {
+			ch <- prometheus.MustNewConstMetric(e.poolErrors, prometheus.GaugeValue, count, algo.Name, kind)
+		}
+	}
+
+	for i, gpu := range data.GPUs {
+		index := fmt.Sprintf("%v", i)
+		if gpu.Card != "" {
+			ch <- prometheus.MustNewConstMetric(e.gpuInfo, prometheus.GaugeValue, 1, index, gpu.Card, gpu.BIOS, gpu.Driver, gpu.Vendor, gpu.Bus)
+		}
+		collectReading(ch, e.gpuTemperature, gpu.Temperature, index)
+		collectReading(ch, e.gpuFanPercent, gpu.FanPercent, index)
+		collectReading(ch, e.gpuFanRPM, gpu.FanRPM, index)
+		collectReading(ch, e.gpuPower, gpu.Power, index)
+		collectReading(ch, e.gpuAveragePower, gpu.AveragePower, index)
+		collectReading(ch, e.gpuPowerLimit, gpu.PowerLimit, index)
+		collectReading(ch, e.gpuPowerLimitWatts, gpu.PowerLimitWatts, index)
+		collectReading(ch, e.gpuCoreClock, gpu.CoreClock, index)
+		collectReading(ch, e.gpuMemoryClock, gpu.MemoryClock, index)
+		collectReading(ch, e.gpuHardwareErrors, gpu.HardwareErrors, index)
+		collectReading(ch, e.gpuLatency, gpu.Latency, index)
+	}
+
+	for _, board := range data.Boards {
+		ch <- prometheus.MustNewConstMetric(e.boardChips, prometheus.GaugeValue, board.ChipsHealthy, board.ID, "healthy")
+		ch <- prometheus.MustNewConstMetric(e.boardChips, prometheus.GaugeValue, board.ChipsFailed, board.ID, "failed")
+		collectReading(ch, e.boardTemperature, board.Temperature, board.ID, "board")
+		collectReading(ch, e.boardTemperature, board.ChipTemperature, board.ID, "chip")
+		collectReading(ch, e.boardFrequency, board.Frequency, board.ID)
+		collectReading(ch, e.boardRate, board.Rate, board.ID)
+		collectReading(ch, e.boardHardwareErrors, board.HardwareErrors, board.ID)
+	}
+
+	for _, fan := range data.Fans {
+		ch <- prometheus.MustNewConstMetric(e.fanRPM, prometheus.GaugeValue, fan.RPM, fan.ID)
+	}
+
+	for _, file := range data.Files {
+		ch <- prometheus.MustNewConstMetric(e.fileInfo, prometheus.GaugeValue, 1, file.Name, file.SHA256)
+	}
+}
+
+// collectReading exports a gauge for readings the miner actually reported.
+func collectReading(ch chan<- prometheus.Metric, desc *prometheus.Desc, value *float64, labels ...string) {
+	if value != nil {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *value, labels...)
+	}
+}
+
+// collectCounter is collectReading for values the miner counts up.
+func collectCounter(ch chan<- prometheus.Metric, desc *prometheus.Desc, value *float64, labels ...string) {
+	if value != nil {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, *value, labels...)
+	}
+}
+
+// Poll collects the miner every interval in the background. Once polling,
+// scrapes are served from the latest snapshot and never reach the miner.
+func (e *Exporter) Poll(interval, timeout time.Duration) {
+	e.mu.Lock()
+	e.polling = true
+	e.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			e.scrape(ctx)
+			cancel()
+
+			<-ticker.C
+		}
+	}()
+}
+
+// scrape collects the miner once and keeps the result as the latest snapshot.
+func (e *Exporter) scrape(ctx context.Context) *snapshot {
+	begin := time.Now()
+	data, err := e.miner.Collect(ctx)
+	s := &snapshot{
+		metrics:  data,
+		err:      err,
+		duration: time.Since(begin),
+		time:     time.Now(),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.last = s
+	if err != nil {
+		e.errors[errorReason(ctx, err)]++
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out: %s", err)
+		}
+		log.Printf("Failed to collect stats from miner %s: %s\n", e.name, err)
+	} else {
+		e.lastSuccessfulAt = s.time
+	}
+
+	return s
+}
+
+func (e *Exporter) collectScrape(ch chan<- prometheus.Metric, last *snapshot) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(e.scrapeDuration, prometheus.GaugeValue, last.duration.Seconds())
+
+	for reason, count := range e.errors {
+		ch <- prometheus.MustNewConstMetric(e.scrapeErrors, prometheus.CounterValue, count, reason)
+	}
+
+	if !e.lastSuccessfulAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(e.lastSuccess, prometheus.GaugeValue, float64(e.lastSuccessfulAt.UnixNano())/1e9)
+	}
+}
+
+// errorReason classifies a failed collection for the scrape errors counter.
+func errorReason(ctx context.Context, err error) string {
+	if ctx.Err() == context.DeadlineExceeded {
+		return "timeout"
+	}
+
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return "timeout"
+	}
+
+	// HTTP backends wrap the errors of the connection in a *url.Error.
+	var authErr *authError
+	if errors.As(err, &authErr) {
+		return "auth"
+	}
+
+	var httpErr *httpError
+	if errors.As(err, &httpErr) && (httpErr.status == http.StatusUnauthorized || httpErr.status == http.StatusForbidden) {
+		return "auth"
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return "connection"
+	}
+
+	return "protocol"
+}
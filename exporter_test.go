@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedMiner struct {
+	mock.Mock
+}
+
+func (m *MockedMiner) Name() string {
+	return "mock"
+}
+
+func (m *MockedMiner) Collect(ctx context.Context) (*Metrics, error) {
+	args := m.Called()
+	metrics, _ := args.Get(0).(*Metrics)
+	return metrics, args.Error(1)
+}
+
+func gather(t *testing.T, exporters ...*Exporter) map[string]*dto.MetricFamily {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(&contextCollector{context.Background(), exporters})
+
+	families, err := registry.Gather()
+	assert.Nil(t, err)
+
+	result := map[string]*dto.MetricFamily{}
+	for _, family := range families {
+		result[family.GetName()] = family
+	}
+	return result
+}
+
+func TestExporterScrapeErrors(t *testing.T) {
+	miner := new(MockedMiner)
+	miner.On("Collect").Return(nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}).Once()
+	miner.On("Collect").Return(nil, errors.New("unexpected reply")).Once()
+	miner.On("Collect").Return(&Metrics{Version: "1.0"}, nil).Once()
+
+	e := NewExporter(miner, "rig", nil)
+
+	metrics := gather(t, e)
+	assert.Equal(t, 0.0, metrics["miner_up"].Metric[0].GetGauge().GetValue())
+	assert.Nil(t, metrics["miner_exporter_last_success_timestamp_seconds"])
+
+	gather(t, e)
+	metrics = gather(t, e)
+
+	assert.Equal(t, 1.0, metrics["miner_up"].Metric[0].GetGauge().GetValue())
+	assert.Equal(t, 2, len(metrics["miner_exporter_scrape_errors_total"].Metric))
+	assert.Equal(t, 1, len(metrics["miner_exporter_last_success_timestamp_seconds"].Metric))
+	assert.Equal(t, 1, len(metrics["miner_exporter_scrape_duration_seconds"].Metric))
+}
+
+func TestExporterPoll(t *testing.T) {
+	miner := new(MockedMiner)
+	miner.On("Collect").Return(&Metrics{Version: "1.0"}, nil).Once()
+
+	e := NewExporter(miner, "rig", nil)
+	e.Poll(time.Hour, time.Second)
+
+	metrics := gather(t, e)
+	for deadline := time.Now().Add(time.Second); metrics["miner_exporter_snapshot_age_seconds"] == nil && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		metrics = gather(t, e)
+	}
+	metrics = gather(t, e)
+
+	assert.Equal(t, 1.0, metrics["miner_up"].Metric[0].GetGauge().GetValue())
+	assert.Equal(t, 1, len(metrics["miner_exporter_snapshot_age_seconds"].Metric))
+	miner.AssertNumberOfCalls(t, "Collect", 1)
+}
+
+func TestErrorReasonHTTPMiner(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, err = NewTRexClient(address).Collect(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "connection", errorReason(context.Background(), err))
+}
+
+func TestExporterUnreportedRates(t *testing.T) {
+	miner := new(MockedMiner)
+	miner.On("Collect").Return(&Metrics{Algorithms: []Algorithm{
+		{Name: "ethash", Rates: Rates{Total: 1000}},
+		{Name: "blake2s", Shares: Shares{Accepted: 12}, Rates: Rates{Unreported: true}},
+	}}, nil)
+
+	metrics := gather(t, NewExporter(miner, "rig", nil))
+
+	assert.Equal(t, 1, len(metrics["miner_rates_total"].Metric))
+	assert.Equal(t, 8, len(metrics["miner_shares"].Metric))
+}
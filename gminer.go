@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+type gminerStat struct {
+	Miner               string  `json:"miner"`
+	Uptime              float64 `json:"uptime"`
+	Algorithm           string  `json:"algorithm"`
+	Server              string  `json:"server"`
+	User                string  `json:"user"`
+	TotalAcceptedShares float64 `json:"total_accepted_shares"`
+	TotalRejectedShares float64 `json:"total_rejected_shares"`
+	TotalStaleShares    float64 `json:"total_stale_shares"`
+	TotalInvalidShares  float64 `json:"total_invalid_shares"`
+	Devices             []struct {
+		Name           string   `json:"name"`
+		Speed          float64  `json:"speed"`
+		AcceptedShares float64  `json:"accepted_shares"`
+		RejectedShares float64  `json:"rejected_shares"`
+		StaleShares    float64  `json:"stale_shares"`
+		InvalidShares  float64  `json:"invalid_shares"`
+		Fan            *float64 `json:"fan"`
+		Temperature    *float64 `json:"temperature"`
+		CoreClock      *float64 `json:"core_clock"`
+		MemoryClock    *float64 `json:"memory_clock"`
+		PowerUsage     *float64 `json:"power_usage"`
+	} `json:"devices"`
+}
+
+// NewGMinerClient reads the /stat of GMiner.
+func NewGMinerClient(address string) *HTTPMinerClient {
+	return newHTTPMinerClient(address, "gminer", collectGMiner)
+}
+
+func collectGMiner(ctx context.Context, api HTTPMinerAPI) (*Metrics, error) {
+	stat := gminerStat{}
+	if err := api.Get(ctx, "/stat", &stat); err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	byGPU := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
+	for _, device := range stat.Devices {
+		total = total + device.Speed
+		byGPU = append(byGPU, device.Speed)
+		sharesByGPU = append(sharesByGPU, Shares{
+			Accepted: device.AcceptedShares,
+			Rejected: device.RejectedShares,
+			Stale:    device.StaleShares,
+			Invalid:  device.InvalidShares,
+		})
+		gpus = append(gpus, GPU{
+			Card:        device.Name,
+			Temperature: device.Temperature,
+			FanPercent:  device.Fan,
+			Power:       device.PowerUsage,
+			CoreClock:   device.CoreClock,
+			MemoryClock: device.MemoryClock,
+		})
+	}
+
+	return &Metrics{
+		// The version comes as "GMiner 2.75".
+		Version: strings.TrimPrefix(stat.Miner, "GMiner "),
+		Uptime:  stat.Uptime,
+		Algorithms: []Algorithm{
+			{
+				Name: strings.ToLower(stat.Algorithm),
+				Shares: Shares{
+					Accepted: stat.TotalAcceptedShares,
+					Rejected: stat.TotalRejectedShares,
+					Stale:    stat.TotalStaleShares,
+					Invalid:  stat.TotalInvalidShares,
+				},
+				Rates: Rates{
+					Total: total,
+					ByGPU: byGPU,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool: Pool{
+					URL:  stat.Server,
+					User: stat.User,
+				},
+			},
+		},
+		GPUs: gpus,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	GMINER_STAT = `{
+   "uptime":7260,
+   "miner":"GMiner 2.75",
+   "algorithm":"Ethash",
+   "stratum_version":"EthereumStratum/1.0.0",
+   "server":"eu1.ethermine.org:4444",
+   "user":"0x5a6f1d5b4b0f9c5c2d8f4c3e2a1b0c9d8e7f6a5b.wupse",
+   "shares_per_minute":1.21,
+   "electricity":0.412,
+   "total_accepted_shares":300,
+   "total_rejected_shares":1,
+   "total_stale_shares":2,
+   "total_invalid_shares":0,
+   "devices":[
+      {
+         "gpu_id":0,
+         "bus_id":"0000:01:00.0",
+         "name":"NVIDIA GeForce RTX 3070",
+         "speed":61200000,
+         "accepted_shares":180,
+         "rejected_shares":0,
+         "stale_shares":1,
+         "invalid_shares":0,
+         "fan":60,
+         "temperature":55,
+         "core_clock":1500,
+         "memory_clock":7000,
+         "power_usage":130
+      },
+      {
+         "gpu_id":1,
+         "bus_id":"0000:02:00.0",
+         "name":"NVIDIA GeForce RTX 3060 Ti",
+         "speed":60800000,
+         "accepted_shares":120,
+         "rejected_shares":1,
+         "stale_shares":1,
+         "invalid_shares":0,
+         "fan":65,
+         "temperature":58,
+         "core_clock":1450,
+         "memory_clock":7000,
+         "power_usage":125
+      }
+   ]
+}`
+)
+
+func TestGMinerCollect(t *testing.T) {
+	server := fixtureServer(map[string]string{"/stat": GMINER_STAT})
+	defer server.Close()
+
+	miner := NewGMinerClient(server.URL)
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "gminer", miner.Name())
+	assert.Equal(t, "2.75", metrics.Version)
+	assert.Equal(t, 7260.0, metrics.Uptime)
+	assert.Equal(t, "ethash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 300.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].Shares.Stale)
+	assert.Equal(t, 122000000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{61200000, 60800000}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].SharesByGPU[1].Rejected)
+	assert.Equal(t, "eu1.ethermine.org:4444", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "0x5a6f1d5b4b0f9c5c2d8f4c3e2a1b0c9d8e7f6a5b.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, "NVIDIA GeForce RTX 3060 Ti", metrics.GPUs[1].Card)
+	assert.Equal(t, 58.0, *metrics.GPUs[1].Temperature)
+	assert.Equal(t, 65.0, *metrics.GPUs[1].FanPercent)
+	assert.Equal(t, 125.0, *metrics.GPUs[1].Power)
+	assert.Equal(t, 7000.0, *metrics.GPUs[1].MemoryClock)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+)
+
+// HTTPMinerAPI fetches the JSON documents of a miner's HTTP API by path.
+type HTTPMinerAPI interface {
+	Get(ctx context.Context, path string, reply interface{}) error
+}
+
+// HTTPMinerClient is the common backend of miners with a plain HTTP JSON
+// API. All they differ in is the layout of their replies, which an adapter
+// turns into Metrics.
+type HTTPMinerClient struct {
+	api     HTTPMinerAPI
+	name    string
+	adapter httpAdapter
+}
+
+type httpAdapter func(ctx context.Context, api HTTPMinerAPI) (*Metrics, error)
+
+type httpMinerAPIClient struct {
+	url string
+}
+
+func (c httpMinerAPIClient) Get(ctx context.Context, path string, reply interface{}) error {
+	return getJSON(ctx, c.url+path, "", reply)
+}
+
+func newHTTPMinerClient(address string, name string, adapter httpAdapter) *HTTPMinerClient {
+	return &HTTPMinerClient{httpMinerAPIClient{baseURL(address)}, name, adapter}
+}
+
+func (c *HTTPMinerClient) Name() string {
+	return c.name
+}
+
+func (c *HTTPMinerClient) Collect(ctx context.Context) (*Metrics, error) {
+	return c.adapter(ctx, c.api)
+}
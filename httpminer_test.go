@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtureServer serves each fixture under its path, like a miner's HTTP API.
+func fixtureServer(fixtures map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fixture))
+	}))
+}
+
+func TestHTTPMinerCollectNotFound(t *testing.T) {
+	server := fixtureServer(map[string]string{})
+	defer server.Close()
+
+	_, err := NewTRexClient(server.URL).Collect(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*httpError).status)
+}
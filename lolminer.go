@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+type lolMinerSummary struct {
+	Software string `json:"Software"`
+	Mining   struct {
+		Algorithm string `json:"Algorithm"`
+	} `json:"Mining"`
+	Session struct {
+		Uptime             float64 `json:"Uptime"`
+		PerformanceSummary float64 `json:"Performance_Summary"`
+		PerformanceUnit    string  `json:"Performance_Unit"`
+		Accepted           float64 `json:"Accepted"`
+		Submitted          float64 `json:"Submitted"`
+	} `json:"Session"`
+	Stratum struct {
+		CurrentPool    string   `json:"Current_Pool"`
+		CurrentUser    string   `json:"Current_User"`
+		AverageLatency *float64 `json:"Average_Latency"`
+	} `json:"Stratum"`
+	GPUs []struct {
+		Name             string   `json:"Name"`
+		Performance      float64  `json:"Performance"`
+		Consumption      *float64 `json:"Consumption (W)"`
+		FanSpeed         *float64 `json:"Fan Speed (%)"`
+		Temp             *float64 `json:"Temp (deg C)"`
+		SessionAccepted  float64  `json:"Session_Accepted"`
+		SessionSubmitted float64  `json:"Session_Submitted"`
+		SessionHWErr     *float64 `json:"Session_HWErr"`
+	} `json:"GPUs"`
+}
+
+// lolMinerUnits scales the performance unit of lolMiner to hashes, or
+// solutions for Equihash, per second.
+var lolMinerUnits = map[string]float64{
+	"kh/s": 1e3,
+	"mh/s": 1e6,
+	"gh/s": 1e9,
+}
+
+// NewLolMinerClient reads the JSON summary of lolMiner.
+func NewLolMinerClient(address string) *HTTPMinerClient {
+	return newHTTPMinerClient(address, "lolminer", collectLolMiner)
+}
+
+func collectLolMiner(ctx context.Context, api HTTPMinerAPI) (*Metrics, error) {
+	summary := lolMinerSummary{}
+	if err := api.Get(ctx, "/summary", &summary); err != nil {
+		return nil, err
+	}
+
+	scale, ok := lolMinerUnits[strings.ToLower(summary.Session.PerformanceUnit)]
+	if !ok {
+		scale = 1
+	}
+
+	byGPU := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
+	for _, gpu := range summary.GPUs {
+		byGPU = append(byGPU, gpu.Performance*scale)
+		sharesByGPU = append(sharesByGPU, Shares{
+			Accepted: gpu.SessionAccepted,
+			Rejected: gpu.SessionSubmitted - gpu.SessionAccepted,
+		})
+		gpus = append(gpus, GPU{
+			Card:           gpu.Name,
+			Temperature:    gpu.Temp,
+			FanPercent:     gpu.FanSpeed,
+			Power:          gpu.Consumption,
+			HardwareErrors: gpu.SessionHWErr,
+		})
+	}
+
+	algorithm := strings.ToLower(summary.Mining.Algorithm)
+	if algorithm == "" {
+		algorithm = "unknown"
+	}
+
+	return &Metrics{
+		// The version comes as "lolMiner 1.29".
+		Version: strings.TrimPrefix(summary.Software, "lolMiner "),
+		Uptime:  summary.Session.Uptime,
+		Algorithms: []Algorithm{
+			{
+				Name: algorithm,
+				Shares: Shares{
+					Accepted: summary.Session.Accepted,
+					Rejected: summary.Session.Submitted - summary.Session.Accepted,
+				},
+				Rates: Rates{
+					Total: summary.Session.PerformanceSummary * scale,
+					ByGPU: byGPU,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool: Pool{
+					URL:     summary.Stratum.CurrentPool,
+					User:    summary.Stratum.CurrentUser,
+					Latency: milli(summary.Stratum.AverageLatency),
+				},
+			},
+		},
+		GPUs: gpus,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	LOLMINER_SUMMARY = `{
+   "Software":"lolMiner 1.29",
+   "Mining":{"Algorithm":"Ethash"},
+   "Session":{
+      "Startup":1615000000,
+      "Startup_String":"2021-03-06 03:06:40",
+      "Uptime":3600,
+      "Last_Update":1615003600,
+      "Active_GPUs":2,
+      "Performance_Summary":61.5,
+      "Performance_Unit":"mh/s",
+      "Accepted":200,
+      "Submitted":203,
+      "TotalPower":240.5
+   },
+   "Stratum":{
+      "Current_Pool":"eth.2miners.com:2020",
+      "Current_User":"wallet.wupse",
+      "Average_Latency":35
+   },
+   "GPUs":[
+      {
+         "Index":0,
+         "Name":"Radeon RX 580 Series",
+         "Performance":30.5,
+         "Consumption (W)":120.2,
+         "Fan Speed (%)":55,
+         "Temp (deg C)":62,
+         "Session_Accepted":100,
+         "Session_Submitted":101,
+         "Session_HWErr":0,
+         "PCIE_Address":"1:0"
+      },
+      {
+         "Index":1,
+         "Name":"Radeon RX 580 Series",
+         "Performance":31,
+         "Consumption (W)":120.3,
+         "Fan Speed (%)":60,
+         "Temp (deg C)":65,
+         "Session_Accepted":100,
+         "Session_Submitted":102,
+         "Session_HWErr":1,
+         "PCIE_Address":"2:0"
+      }
+   ]
+}`
+)
+
+func TestLolMinerCollect(t *testing.T) {
+	server := fixtureServer(map[string]string{"/summary": LOLMINER_SUMMARY})
+	defer server.Close()
+
+	miner := NewLolMinerClient(server.URL)
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "lolminer", miner.Name())
+	assert.Equal(t, "1.29", metrics.Version)
+	assert.Equal(t, 3600.0, metrics.Uptime)
+	assert.Equal(t, "ethash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 200.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 3.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 61500000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{30500000, 31000000}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].SharesByGPU[1].Rejected)
+	assert.Equal(t, "eth.2miners.com:2020", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "wallet.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, 0.035, *metrics.Algorithms[0].Pool.Latency)
+	assert.Equal(t, "Radeon RX 580 Series", metrics.GPUs[1].Card)
+	assert.Equal(t, 65.0, *metrics.GPUs[1].Temperature)
+	assert.Equal(t, 60.0, *metrics.GPUs[1].FanPercent)
+	assert.Equal(t, 120.3, *metrics.GPUs[1].Power)
+	assert.Equal(t, 1.0, *metrics.GPUs[1].HardwareErrors)
+}
@@ -1,128 +1,167 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
 	namespace = "miner"
 )
 
-type Metrics struct {
-	Version    string
-	Uptime     float64
-	Algorithms []Algorithm
-}
-
-type Algorithm struct {
-	Name   string
-	Shares Shares
-	Rates  Rates
-}
-
-type Shares struct {
-	Accepted float64
-	Rejected float64
-	Stale    float64
-}
-
-type Rates struct {
-	Total float64
-	ByGPU []float64
-}
-
-type Exporter struct {
-	miner      Miner
-	up         *prometheus.Desc
-	uptime     *prometheus.Desc
-	info       *prometheus.Desc
-	rates      *prometheus.Desc
-	ratesTotal *prometheus.Desc
-	shares     *prometheus.Desc
-}
-
 type Miner interface {
 	Name() string
-	Collect() (*Metrics, error)
-}
-
-func NewExporter(miner Miner) *Exporter {
-	return &Exporter{
-		miner: miner,
-		up: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "", "up"),
-			"Could the miner be reached.",
-			nil,
-			prometheus.Labels{"name": miner.Name()},
-		),
-		uptime: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "", "uptime"),
-			"Number of seconds since the miner started.",
-			nil,
-			prometheus.Labels{"name": miner.Name()},
-		),
-		info: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "", "info"),
-			"Information about this miner",
-			[]string{"version"},
-			prometheus.Labels{"name": miner.Name()},
-		),
-		rates: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "", "rates"),
-			"Mining rate by Algorithm and GPU",
-			[]string{"algorithm", "gpu"},
-			prometheus.Labels{"name": miner.Name()},
-		),
-		shares: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "", "shares"),
-			"Shares by Algorithm and Status",
-			[]string{"algorithm", "status"},
-			prometheus.Labels{"name": miner.Name()},
-		),
-		ratesTotal: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "rates", "total"),
-			"Mining rate total by algorithm",
-			[]string{"algorithm"},
-			prometheus.Labels{"name": miner.Name()},
-		),
-	}
+	Collect(ctx context.Context) (*Metrics, error)
 }
 
 func main() {
 	var (
 		listenAddress = flag.String("web.listen-address", ":9278", "Address to listen on for web interface and telemetry.")
 		metricsPath   = flag.String("web.telemetry-path", "/metrics", "Path under which to expose metrics.")
+		bminerFlag    = flag.String("bminer", "", "Enable and read Bminer metrics from this address")
 		ccminerFlag   = flag.String("ccminer", "", "Enable and read CCMiner metrics from this address")
+		cgminerFlag   = flag.String("cgminer", "", "Enable and read cgminer API compatible metrics from this address")
+		cgminerASIC   = flag.Bool("cgminer.asic", false, "Read hashboard and fan details of cgminer based ASICs")
 		cdmFlag       = flag.String("claymoredualminer", "", "Enable and read Claymore Dual Miner metrics from this address")
+		cdmVariant    = flag.String("claymore.variant", "eth", "Claymore protocol miner to expect: eth, phoenix, cryptonote or zcash")
+		cdmPassword   = flag.String("claymore.password", "", "API password the Claymore protocol miner was started with (-mpsw)")
+		cdmFiles      = flag.String("claymore.files", "", "Comma separated configuration files to read from the Claymore protocol miner, like config.txt,epools.txt")
+		cdmGetstat2   = flag.Bool("claymore.getstat2", false, "Read per GPU shares of the Claymore protocol miner using miner_getstat2")
 		dstmFlag      = flag.String("dstm", "", "Enable and read DSTM metrics from this address")
+		ethminerFlag  = flag.String("ethminer", "", "Enable and read ethminer metrics from this address")
+		ewbfFlag      = flag.String("ewbf", "", "Enable and read EWBF metrics from this address")
+		excavatorFlag = flag.String("excavator", "", "Enable and read Excavator metrics from this address")
+		gminerFlag    = flag.String("gminer", "", "Enable and read GMiner metrics from this address")
+		lolminerFlag  = flag.String("lolminer", "", "Enable and read lolMiner metrics from this address")
+		minizFlag     = flag.String("miniz", "", "Enable and read miniZ metrics from this address")
+		nbminerFlag   = flag.String("nbminer", "", "Enable and read NBMiner metrics from this address")
+		srbminerFlag  = flag.String("srbminer", "", "Enable and read SRBMiner metrics from this address")
+		trexFlag      = flag.String("trex", "", "Enable and read T-Rex metrics from this address")
+		xmrigFlag     = flag.String("xmrig", "", "Enable and read XMRig metrics from this address")
+		xmrigToken    = flag.String("xmrig.token", "", "Access token for the XMRig HTTP API")
+		xmrstakFlag   = flag.String("xmrstak", "", "Enable and read xmr-stak metrics from this address")
+		adminToken    = flag.String("admin.token", "", "Bearer token enabling /admin to control Claymore protocol miners and push their configuration files")
+		configFile    = flag.String("config.file", "", "Path to a YAML file listing the miners to export")
+		scrapeTimeout = flag.Duration("scrape.timeout", 10*time.Second, "Timeout for collecting a miner if Prometheus does not announce one")
+		pollInterval  = flag.Duration("poll.interval", 0, "Collect miners in the background at this interval and serve scrapes from the latest snapshot (0 disables polling)")
 	)
 	flag.Parse()
 
+	targets := []TargetConfig{}
+	if *configFile != "" {
+		config, err := LoadConfig(*configFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		targets = append(targets, config.Targets...)
+	}
+
+	if *bminerFlag != "" {
+		targets = append(targets, TargetConfig{Type: "bminer", Address: *bminerFlag})
+	}
+
 	if *ccminerFlag != "" {
-		prometheus.MustRegister(NewExporter(NewCCMinerClient(*ccminerFlag)))
+		targets = append(targets, TargetConfig{Type: "ccminer", Address: *ccminerFlag})
+	}
+
+	if *cgminerFlag != "" {
+		targets = append(targets, TargetConfig{Type: "cgminer", Address: *cgminerFlag, ASIC: *cgminerASIC})
 	}
 
 	if *cdmFlag != "" {
-		prometheus.MustRegister(NewExporter(NewClaymoreDualMinerClient("tcp", *cdmFlag)))
+		targets = append(targets, TargetConfig{
+			Type:     "claymore",
+			Address:  *cdmFlag,
+			Variant:  *cdmVariant,
+			Password: *cdmPassword,
+			Getstat2: *cdmGetstat2,
+			Files:    splitList(*cdmFiles),
+		})
 	}
 
 	if *dstmFlag != "" {
-		prometheus.MustRegister(NewExporter(NewDSTMClient(*dstmFlag)))
+		targets = append(targets, TargetConfig{Type: "dstm", Address: *dstmFlag})
+	}
+
+	if *ethminerFlag != "" {
+		targets = append(targets, TargetConfig{Type: "ethminer", Address: *ethminerFlag})
+	}
+
+	if *ewbfFlag != "" {
+		targets = append(targets, TargetConfig{Type: "ewbf", Address: *ewbfFlag})
+	}
+
+	if *excavatorFlag != "" {
+		targets = append(targets, TargetConfig{Type: "excavator", Address: *excavatorFlag})
+	}
+
+	if *gminerFlag != "" {
+		targets = append(targets, TargetConfig{Type: "gminer", Address: *gminerFlag})
+	}
+
+	if *lolminerFlag != "" {
+		targets = append(targets, TargetConfig{Type: "lolminer", Address: *lolminerFlag})
 	}
 
-	http.Handle("/metrics", promhttp.Handler())
+	if *minizFlag != "" {
+		targets = append(targets, TargetConfig{Type: "miniz", Address: *minizFlag})
+	}
+
+	if *nbminerFlag != "" {
+		targets = append(targets, TargetConfig{Type: "nbminer", Address: *nbminerFlag})
+	}
+
+	if *srbminerFlag != "" {
+		targets = append(targets, TargetConfig{Type: "srbminer", Address: *srbminerFlag})
+	}
+
+	if *trexFlag != "" {
+		targets = append(targets, TargetConfig{Type: "trex", Address: *trexFlag})
+	}
+
+	if *xmrigFlag != "" {
+		targets = append(targets, TargetConfig{Type: "xmrig", Address: *xmrigFlag, Token: *xmrigToken})
+	}
+
+	if *xmrstakFlag != "" {
+		targets = append(targets, TargetConfig{Type: "xmrstak", Address: *xmrstakFlag})
+	}
+
+	exporters, err := NewExporters(targets)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i, t := range targets {
+		interval := t.PollInterval
+		if interval == 0 {
+			interval = *pollInterval
+		}
+
+		if interval > 0 {
+			exporters[i].Poll(interval, *scrapeTimeout)
+		}
+	}
+
+	http.Handle("/metrics", NewMetricsHandler(exporters, *scrapeTimeout, prometheus.DefaultGatherer))
+	http.Handle("/probe", NewProbeHandler(*scrapeTimeout))
+	if *adminToken != "" {
+		http.Handle("/admin/", NewAdminHandler(exporters, *adminToken, *scrapeTimeout))
+	}
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
              <head><title>Miner Exporter</title></head>
              <body>
              <h1>Miner Exporter</h1>
              <p><a href='` + *metricsPath + `'>Metrics</a></p>
+             <p><a href='/probe?type=ccminer&target=localhost:4068'>Probe ccminer on localhost:4068</a></p>
              </body>
              </html>`))
 	})
@@ -130,37 +169,10 @@ func main() {
 	log.Fatal(http.ListenAndServe(*listenAddress, nil))
 }
 
-func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
-	ch <- e.up
-	ch <- e.uptime
-	ch <- e.info
-	ch <- e.rates
-	ch <- e.ratesTotal
-	ch <- e.shares
-}
-
-func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
-	data, err := e.miner.Collect()
-	if err != nil {
-		ch <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 0)
-		log.Printf("Failed to collect stats from miner: %s\n", err)
-		return
-	}
-
-	fmt.Printf("%+v\n", data)
-
-	ch <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 1)
-	ch <- prometheus.MustNewConstMetric(e.info, prometheus.GaugeValue, 1, data.Version)
-	ch <- prometheus.MustNewConstMetric(e.uptime, prometheus.CounterValue, data.Uptime)
-
-	for _, algo := range data.Algorithms {
-		for gpu, r := range algo.Rates.ByGPU {
-			ch <- prometheus.MustNewConstMetric(e.rates, prometheus.GaugeValue, r, algo.Name, fmt.Sprintf("%v", gpu))
-		}
-
-		ch <- prometheus.MustNewConstMetric(e.ratesTotal, prometheus.GaugeValue, algo.Rates.Total, algo.Name)
-		ch <- prometheus.MustNewConstMetric(e.shares, prometheus.GaugeValue, algo.Shares.Accepted, algo.Name, "accepted")
-		ch <- prometheus.MustNewConstMetric(e.shares, prometheus.GaugeValue, algo.Shares.Rejected, algo.Name, "rejected")
-		ch <- prometheus.MustNewConstMetric(e.shares, prometheus.GaugeValue, algo.Shares.Stale, algo.Name, "stale")
+// splitList splits a comma separated flag value, which is empty if unset.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
 	}
+	return strings.Split(value, ",")
 }
@@ -0,0 +1,154 @@
+package main
+
+import "strconv"
+
+type Metrics struct {
+	Version    string
+	Uptime     float64
+	Algorithms []Algorithm
+	GPUs       []GPU
+
+	// Memory is the memory in bytes the miner uses for its own statistics.
+	Memory *float64
+
+	// Boards and Fans are reported by ASICs only.
+	Boards []Board
+	Fans   []Fan
+
+	// Files lists the configuration files read from the miner.
+	Files []File
+}
+
+type Algorithm struct {
+	Name   string
+	Shares Shares
+	Rates  Rates
+
+	// SharesByGPU breaks the shares down by GPU, if the miner reports it.
+	SharesByGPU []Shares
+
+	Pool Pool
+}
+
+type Shares struct {
+	Accepted float64
+	Rejected float64
+	Stale    float64
+	Invalid  float64
+}
+
+type Rates struct {
+	Total float64
+	ByGPU []float64
+
+	// AverageByGPU is the rate of each GPU averaged by the miner. GPUs the
+	// miner has no average for yet are nil.
+	AverageByGPU []*float64
+
+	// EfficiencyByGPU is the rate per watt of each GPU, if known.
+	EfficiencyByGPU []float64
+
+	// Windows holds the total rate averaged over the named time windows
+	// in seconds, like "60s" or "900s", for miners that report several.
+	Windows map[string]float64
+
+	// Unreported is set for algorithms the miner reports shares but no
+	// rate for, which are then left out of the rates instead of reading 0.
+	Unreported bool
+}
+
+// File identifies the content of a configuration file by its hash, so that
+// files differing across miners stand out.
+type File struct {
+	Name   string
+	SHA256 string
+}
+
+// Pool describes the pool connection an algorithm is currently mining on.
+// Latency, LastShare and Connected are in seconds. Up is 1 while the miner
+// is subscribed to the pool and 0 otherwise. Switches counts how often the
+// miner changed pools. Errors counts the recent errors the miner logged for
+// the pool by type, like "low_difficulty" or "connection".
+type Pool struct {
+	URL         string
+	User        string
+	Up          *float64
+	Difficulty  *float64
+	Latency     *float64
+	Disconnects *float64
+	LastShare   *float64
+	Connected   *float64
+	Switches    *float64
+	Errors      map[string]float64
+}
+
+// GPU holds the hardware readings of a single GPU, indexed like the rates of
+// each algorithm. Readings a miner does not report are left nil. Vendor is
+// the PCI vendor id and Bus the PCI bus of the GPU. PowerLimit is the power
+// limit setting as the miner reports it, like ccminer's flag, while
+// PowerLimitWatts is the limit itself.
+type GPU struct {
+	Card            string
+	BIOS            string
+	Driver          string
+	Vendor          string
+	Bus             string
+	Temperature     *float64
+	FanPercent      *float64
+	FanRPM          *float64
+	Power           *float64
+	AveragePower    *float64
+	PowerLimit      *float64
+	PowerLimitWatts *float64
+	CoreClock       *float64
+	MemoryClock     *float64
+	HardwareErrors  *float64
+	Latency         *float64
+}
+
+// Board holds the readings of an ASIC hashboard, identified by the number
+// of its chain. Rate is in H/s.
+type Board struct {
+	ID              string
+	ChipsHealthy    float64
+	ChipsFailed     float64
+	Temperature     *float64
+	ChipTemperature *float64
+	Frequency       *float64
+	Rate            *float64
+	HardwareErrors  *float64
+}
+
+type Fan struct {
+	ID  string
+	RPM float64
+}
+
+func reading(value float64) *float64 {
+	return &value
+}
+
+// boolReading turns a flag into a reading of 1 or 0.
+func boolReading(value bool) *float64 {
+	if value {
+		return reading(1)
+	}
+	return reading(0)
+}
+
+// parseReading returns nil for readings the miner left out or garbled.
+func parseReading(input string) *float64 {
+	value, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// milli converts a reading in milliwatts or milliseconds to the base unit.
+func milli(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	return reading(*value / 1000)
+}
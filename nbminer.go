@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+type nbminerStatus struct {
+	Version   string `json:"version"`
+	StartTime int64  `json:"start_time"`
+	Miner     struct {
+		TotalHashrateRaw float64 `json:"total_hashrate_raw"`
+		Devices          []struct {
+			Info           string   `json:"info"`
+			HashrateRaw    float64  `json:"hashrate_raw"`
+			AcceptedShares float64  `json:"accepted_shares"`
+			RejectedShares float64  `json:"rejected_shares"`
+			InvalidShares  float64  `json:"invalid_shares"`
+			Temperature    *float64 `json:"temperature"`
+			Fan            *float64 `json:"fan"`
+			Power          *float64 `json:"power"`
+			CoreClock      *float64 `json:"core_clock"`
+			MemClock       *float64 `json:"mem_clock"`
+		} `json:"devices"`
+	} `json:"miner"`
+	Stratum struct {
+		Algorithm      string   `json:"algorithm"`
+		URL            string   `json:"url"`
+		User           string   `json:"user"`
+		Latency        *float64 `json:"latency"`
+		AcceptedShares float64  `json:"accepted_shares"`
+		RejectedShares float64  `json:"rejected_shares"`
+		InvalidShares  float64  `json:"invalid_shares"`
+	} `json:"stratum"`
+}
+
+// NewNBMinerClient reads the /api/v1/status of NBMiner. Only the primary
+// algorithm of dual mining is reported.
+func NewNBMinerClient(address string) *HTTPMinerClient {
+	return newHTTPMinerClient(address, "nbminer", collectNBMiner)
+}
+
+func collectNBMiner(ctx context.Context, api HTTPMinerAPI) (*Metrics, error) {
+	status := nbminerStatus{}
+	if err := api.Get(ctx, "/api/v1/status", &status); err != nil {
+		return nil, err
+	}
+
+	byGPU := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
+	for _, device := range status.Miner.Devices {
+		byGPU = append(byGPU, device.HashrateRaw)
+		sharesByGPU = append(sharesByGPU, Shares{
+			Accepted: device.AcceptedShares,
+			Rejected: device.RejectedShares,
+			Invalid:  device.InvalidShares,
+		})
+		gpus = append(gpus, GPU{
+			Card:        device.Info,
+			Temperature: device.Temperature,
+			FanPercent:  device.Fan,
+			Power:       device.Power,
+			CoreClock:   device.CoreClock,
+			MemoryClock: device.MemClock,
+		})
+	}
+
+	uptime := 0.0
+	if status.StartTime > 0 {
+		uptime = time.Since(time.Unix(status.StartTime, 0)).Seconds()
+	}
+
+	return &Metrics{
+		Version: status.Version,
+		Uptime:  uptime,
+		Algorithms: []Algorithm{
+			{
+				Name: status.Stratum.Algorithm,
+				Shares: Shares{
+					Accepted: status.Stratum.AcceptedShares,
+					Rejected: status.Stratum.RejectedShares,
+					Invalid:  status.Stratum.InvalidShares,
+				},
+				Rates: Rates{
+					Total: status.Miner.TotalHashrateRaw,
+					ByGPU: byGPU,
+				},
+				SharesByGPU: sharesByGPU,
+				Pool: Pool{
+					URL:     status.Stratum.URL,
+					User:    status.Stratum.User,
+					Latency: milli(status.Stratum.Latency),
+				},
+			},
+		},
+		GPUs: gpus,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	NBMINER_STATUS = `{
+   "miner":{
+      "devices":[
+         {
+            "core_clock":1708,
+            "core_utilization":100,
+            "fan":55,
+            "hashrate":"30.50 M",
+            "hashrate_raw":30500000,
+            "id":0,
+            "info":"GeForce GTX 1070",
+            "mem_clock":4004,
+            "mem_utilization":90,
+            "pci_bus_id":1,
+            "power":120,
+            "temperature":62,
+            "accepted_shares":100,
+            "rejected_shares":1,
+            "invalid_shares":0
+         },
+         {
+            "core_clock":1695,
+            "core_utilization":100,
+            "fan":58,
+            "hashrate":"30.10 M",
+            "hashrate_raw":30100000,
+            "id":1,
+            "info":"GeForce GTX 1070",
+            "mem_clock":4004,
+            "mem_utilization":90,
+            "pci_bus_id":2,
+            "power":118,
+            "temperature":64,
+            "accepted_shares":97,
+            "rejected_shares":0,
+            "invalid_shares":0
+         }
+      ],
+      "total_hashrate":"60.60 M",
+      "total_hashrate_raw":60600000,
+      "total_power_consume":238
+   },
+   "reboot_times":0,
+   "start_time":1615000000,
+   "stratum":{
+      "accepted_shares":197,
+      "algorithm":"ethash",
+      "difficulty":"4.00 G",
+      "dual_mine":false,
+      "latency":38,
+      "rejected_shares":1,
+      "invalid_shares":0,
+      "url":"eth.f2pool.com:6688",
+      "use_ssl":false,
+      "user":"wallet.wupse"
+   },
+   "version":"39.5"
+}`
+)
+
+func TestNBMinerCollect(t *testing.T) {
+	server := fixtureServer(map[string]string{"/api/v1/status": NBMINER_STATUS})
+	defer server.Close()
+
+	miner := NewNBMinerClient(server.URL)
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "nbminer", miner.Name())
+	assert.Equal(t, "39.5", metrics.Version)
+	assert.True(t, metrics.Uptime > 0)
+	assert.Equal(t, "ethash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 197.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 60600000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{30500000, 30100000}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, 97.0, metrics.Algorithms[0].SharesByGPU[1].Accepted)
+	assert.Equal(t, "eth.f2pool.com:6688", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "wallet.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, 0.038, *metrics.Algorithms[0].Pool.Latency)
+	assert.Equal(t, "GeForce GTX 1070", metrics.GPUs[0].Card)
+	assert.Equal(t, 62.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 55.0, *metrics.GPUs[0].FanPercent)
+	assert.Equal(t, 120.0, *metrics.GPUs[0].Power)
+	assert.Equal(t, 4004.0, *metrics.GPUs[0].MemoryClock)
+}
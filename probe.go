@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// NewProbeHandler collects a single miner given by the type and target query
+// parameters into a fresh registry, in the style of the blackbox_exporter.
+func NewProbeHandler(timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probe(w, r, timeout)
+	})
+}
+
+func probe(w http.ResponseWriter, r *http.Request, timeout time.Duration) {
+	params := r.URL.Query()
+
+	target := TargetConfig{
+		Type:    params.Get("type"),
+		Address: params.Get("target"),
+		Name:    params.Get("name"),
+	}
+
+	miner, err := NewMiner(target)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid probe: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	name := target.Name
+	if name == "" {
+		name = miner.Name()
+	}
+
+	exporter := NewExporter(miner, name, nil)
+	NewMetricsHandler([]*Exporter{exporter}, timeout).ServeHTTP(w, r)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func probeRequest(params url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	NewProbeHandler(time.Second).ServeHTTP(w, httptest.NewRequest("GET", "/probe?"+params.Encode(), nil))
+	return w
+}
+
+func TestProbe(t *testing.T) {
+	server := fixtureServer(map[string]string{"/summary": TREX_SUMMARY})
+	defer server.Close()
+
+	w := probeRequest(url.Values{"type": {"trex"}, "target": {server.URL}, "name": {"rig1"}})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `miner_up{name="rig1"} 1`)
+	assert.Contains(t, w.Body.String(), `miner_rates_total{algorithm="ethash",name="rig1"}`)
+}
+
+func TestProbeInvalid(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, probeRequest(url.Values{"type": {"bogus"}, "target": {"localhost:4068"}}).Code)
+	assert.Equal(t, http.StatusBadRequest, probeRequest(url.Values{"type": {"ccminer"}}).Code)
+}
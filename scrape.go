@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeTimeoutOffset leaves Prometheus some room to receive the response
+// before its own scrape timeout fires.
+const scrapeTimeoutOffset = 500 * time.Millisecond
+
+// scrapeContext derives the deadline for collecting miners from the timeout
+// Prometheus announces with each scrape, falling back to the given default.
+func scrapeContext(r *http.Request, fallback time.Duration) (context.Context, context.CancelFunc) {
+	timeout := fallback
+
+	if header := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); header != "" {
+		seconds, err := strconv.ParseFloat(header, 64)
+		if err == nil && seconds > 0 {
+			timeout = time.Duration(seconds * float64(time.Second))
+			if timeout > scrapeTimeoutOffset {
+				timeout = timeout - scrapeTimeoutOffset
+			}
+		}
+	}
+
+	return context.WithTimeout(r.Context(), timeout)
+}
+
+// contextCollector binds exporters to the context of a single scrape.
+type contextCollector struct {
+	ctx       context.Context
+	exporters []*Exporter
+}
+
+func (c *contextCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, e := range c.exporters {
+		e.Describe(ch)
+	}
+}
+
+// Collect scrapes all miners in parallel, so that a slow miner only delays
+// the scrape up to its deadline instead of adding up with all others.
+func (c *contextCollector) Collect(ch chan<- prometheus.Metric) {
+	wg := sync.WaitGroup{}
+	wg.Add(len(c.exporters))
+
+	for _, e := range c.exporters {
+		go func(e *Exporter) {
+			defer wg.Done()
+			e.Collect(c.ctx, ch)
+		}(e)
+	}
+
+	wg.Wait()
+}
+
+// NewMetricsHandler serves the given exporters together with the metrics of
+// the gatherers, collecting the miners within the scrape's deadline.
+func NewMetricsHandler(exporters []*Exporter, timeout time.Duration, gatherers ...prometheus.Gatherer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := scrapeContext(r, timeout)
+		defer cancel()
+
+		registry := prometheus.NewRegistry()
+		if err := registry.Register(&contextCollector{ctx, exporters}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		promhttp.HandlerFor(append(prometheus.Gatherers{registry}, gatherers...), promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrapeContext(t *testing.T) {
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", "5")
+
+	ctx, cancel := scrapeContext(r, time.Minute)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.InDelta(t, (5*time.Second - scrapeTimeoutOffset).Seconds(), time.Until(deadline).Seconds(), 0.1)
+
+	r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", "bogus")
+	ctx, cancel = scrapeContext(r, time.Minute)
+	defer cancel()
+
+	deadline, _ = ctx.Deadline()
+	assert.InDelta(t, time.Minute.Seconds(), time.Until(deadline).Seconds(), 0.1)
+}
+
+func TestScrapeHungMiner(t *testing.T) {
+	// The listener accepts connections but never replies.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	exporter := NewExporter(NewCCMinerClient(listener.Addr().String()), "rig1", nil)
+
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", "0.7")
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	NewMetricsHandler([]*Exporter{exporter}, time.Minute).ServeHTTP(w, r)
+
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `miner_up{name="rig1"} 0`)
+	assert.Contains(t, w.Body.String(), `miner_exporter_scrape_errors_total{name="rig1",reason="timeout"} 1`)
+}
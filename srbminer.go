@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+)
+
+type srbminerStats struct {
+	MinerVersion string  `json:"miner_version"`
+	MiningTime   float64 `json:"mining_time"`
+	GPUDevices   []struct {
+		Device      string   `json:"device"`
+		Model       string   `json:"model"`
+		Temperature *float64 `json:"temperature"`
+		FanSpeedRPM *float64 `json:"fan_speed_rpm"`
+		Power       *float64 `json:"power"`
+		CoreClock   *float64 `json:"core_clock"`
+		MemoryClock *float64 `json:"memory_clock"`
+	} `json:"gpu_devices"`
+	Algorithms []struct {
+		Name string `json:"name"`
+		Pool struct {
+			Pool       string   `json:"pool"`
+			Worker     string   `json:"worker"`
+			Difficulty *float64 `json:"difficulty"`
+			Uptime     *float64 `json:"uptime"`
+			Latency    *float64 `json:"latency"`
+		} `json:"pool"`
+		Shares struct {
+			Accepted      float64 `json:"accepted"`
+			Rejected      float64 `json:"rejected"`
+			RejectedStale float64 `json:"rejected_stale"`
+		} `json:"shares"`
+		// Rates are keyed by device, like "gpu0", and "total".
+		Hashrate struct {
+			CPU map[string]float64 `json:"cpu"`
+			GPU map[string]float64 `json:"gpu"`
+		} `json:"hashrate"`
+	} `json:"algorithms"`
+}
+
+// NewSRBMinerClient reads the JSON stats of SRBMiner-MULTI.
+func NewSRBMinerClient(address string) *HTTPMinerClient {
+	return newHTTPMinerClient(address, "srbminer", collectSRBMiner)
+}
+
+func collectSRBMiner(ctx context.Context, api HTTPMinerAPI) (*Metrics, error) {
+	stats := srbminerStats{}
+	if err := api.Get(ctx, "/", &stats); err != nil {
+		return nil, err
+	}
+
+	gpus := []GPU{}
+	for _, device := range stats.GPUDevices {
+		gpus = append(gpus, GPU{
+			Card:        device.Model,
+			Temperature: device.Temperature,
+			FanRPM:      device.FanSpeedRPM,
+			Power:       device.Power,
+			CoreClock:   device.CoreClock,
+			MemoryClock: device.MemoryClock,
+		})
+	}
+
+	algorithms := []Algorithm{}
+	for _, algo := range stats.Algorithms {
+		byGPU := []float64{}
+		for _, device := range stats.GPUDevices {
+			byGPU = append(byGPU, algo.Hashrate.GPU[device.Device])
+		}
+
+		algorithms = append(algorithms, Algorithm{
+			Name: algo.Name,
+			Shares: Shares{
+				Accepted: algo.Shares.Accepted,
+				Rejected: algo.Shares.Rejected,
+				Stale:    algo.Shares.RejectedStale,
+			},
+			Rates: Rates{
+				Total: algo.Hashrate.GPU["total"] + algo.Hashrate.CPU["total"],
+				ByGPU: byGPU,
+			},
+			Pool: Pool{
+				URL:        algo.Pool.Pool,
+				User:       algo.Pool.Worker,
+				Difficulty: algo.Pool.Difficulty,
+				Latency:    milli(algo.Pool.Latency),
+				Connected:  algo.Pool.Uptime,
+			},
+		})
+	}
+
+	return &Metrics{
+		Version:    stats.MinerVersion,
+		Uptime:     stats.MiningTime,
+		Algorithms: algorithms,
+		GPUs:       gpus,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	SRBMINER_STATS = `{
+   "rig_name":"wupse",
+   "miner_version":"0.9.4",
+   "mining_time":5400,
+   "total_cpu_workers":0,
+   "total_gpu_workers":2,
+   "driver_version":"20.45",
+   "gpu_devices":[
+      {"device":"gpu0", "device_id":0, "model":"Radeon RX 580", "bus_id":1, "temperature":61, "fan_speed_rpm":1850, "power":118, "core_clock":1340, "memory_clock":2150},
+      {"device":"gpu1", "device_id":1, "model":"Radeon RX 5700", "bus_id":3, "temperature":58, "fan_speed_rpm":1600, "power":110, "core_clock":1350, "memory_clock":1800}
+   ],
+   "algorithms":[
+      {
+         "id":0,
+         "name":"ethash",
+         "pool":{"pool":"eu1.ethermine.org:4444", "worker":"wallet.wupse", "difficulty":4000000000, "uptime":5390, "latency":41},
+         "shares":{"total":210, "accepted":207, "rejected":1, "rejected_stale":2},
+         "hashrate":{
+            "cpu":{"total":0},
+            "gpu":{"gpu0":30200000, "gpu1":54800000, "total":85000000}
+         }
+      }
+   ]
+}`
+)
+
+func TestSRBMinerCollect(t *testing.T) {
+	server := fixtureServer(map[string]string{"/": SRBMINER_STATS})
+	defer server.Close()
+
+	miner := NewSRBMinerClient(server.URL)
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "srbminer", miner.Name())
+	assert.Equal(t, "0.9.4", metrics.Version)
+	assert.Equal(t, 5400.0, metrics.Uptime)
+	assert.Equal(t, "ethash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 207.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].Shares.Stale)
+	assert.Equal(t, 85000000.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{30200000, 54800000}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, "eu1.ethermine.org:4444", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, "wallet.wupse", metrics.Algorithms[0].Pool.User)
+	assert.Equal(t, 0.041, *metrics.Algorithms[0].Pool.Latency)
+	assert.Equal(t, 5390.0, *metrics.Algorithms[0].Pool.Connected)
+	assert.Equal(t, "Radeon RX 5700", metrics.GPUs[1].Card)
+	assert.Equal(t, 58.0, *metrics.GPUs[1].Temperature)
+	assert.Equal(t, 1600.0, *metrics.GPUs[1].FanRPM)
+	assert.Equal(t, 110.0, *metrics.GPUs[1].Power)
+}
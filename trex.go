@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+)
+
+type trexSummary struct {
+	Version        string  `json:"version"`
+	Uptime         float64 `json:"uptime"`
+	Algorithm      string  `json:"algorithm"`
+	Hashrate       float64 `json:"hashrate"`
+	HashrateMinute float64 `json:"hashrate_minute"`
+	HashrateHour   float64 `json:"hashrate_hour"`
+	HashrateDay    float64 `json:"hashrate_day"`
+	AcceptedCount  float64 `json:"accepted_count"`
+	RejectedCount  float64 `json:"rejected_count"`
+	InvalidCount   float64 `json:"invalid_count"`
+	ActivePool     struct {
+		URL     string   `json:"url"`
+		User    string   `json:"user"`
+		Ping    *float64 `json:"ping"`
+		Retries *float64 `json:"retries"`
+	} `json:"active_pool"`
+	GPUs []struct {
+		Name        string   `json:"name"`
+		Hashrate    float64  `json:"hashrate"`
+		Temperature *float64 `json:"temperature"`
+		FanSpeed    *float64 `json:"fan_speed"`
+		Power       *float64 `json:"power"`
+		PowerAvr    *float64 `json:"power_avr"`
+		CClock      *float64 `json:"cclock"`
+		MClock      *float64 `json:"mclock"`
+		Shares      struct {
+			AcceptedCount float64 `json:"accepted_count"`
+			RejectedCount float64 `json:"rejected_count"`
+			InvalidCount  float64 `json:"invalid_count"`
+		} `json:"shares"`
+	} `json:"gpus"`
+}
+
+// NewTRexClient reads the /summary of T-Rex.
+func NewTRexClient(address string) *HTTPMinerClient {
+	return newHTTPMinerClient(address, "trex", collectTRex)
+}
+
+func collectTRex(ctx context.Context, api HTTPMinerAPI) (*Metrics, error) {
+	summary := trexSummary{}
+	if err := api.Get(ctx, "/summary", &summary); err != nil {
+		return nil, err
+	}
+
+	byGPU := []float64{}
+	sharesByGPU := []Shares{}
+	gpus := []GPU{}
+	for _, gpu := range summary.GPUs {
+		byGPU = append(byGPU, gpu.Hashrate)
+		sharesByGPU = append(sharesByGPU, Shares{
+			Accepted: gpu.Shares.AcceptedCount,
+			Rejected: gpu.Shares.RejectedCount,
+			Invalid:  gpu.Shares.InvalidCount,
+		})
+		gpus = append(gpus, GPU{
+			Card:         gpu.Name,
+			Temperature:  gpu.Temperature,
+			FanPercent:   gpu.FanSpeed,
+			Power:        gpu.Power,
+			AveragePower: gpu.PowerAvr,
+			CoreClock:    gpu.CClock,
+			MemoryClock:  gpu.MClock,
+		})
+	}
+
+	return &Metrics{
+		Version: summary.Version,
+		Uptime:  summary.Uptime,
+		Algorithms: []Algorithm{
+			{
+				Name: summary.Algorithm,
+				Shares: Shares{
+					Accepted: summary.AcceptedCount,
+					Rejected: summary.RejectedCount,
+					Invalid:  summary.InvalidCount,
+				},
+				Rates: Rates{
+					Total: summary.Hashrate,
+					ByGPU: byGPU,
+					Windows: map[string]float64{
+						"60s":    summary.HashrateMinute,
+						"3600s":  summary.HashrateHour,
+						"86400s": summary.HashrateDay,
+					},
+				},
+				SharesByGPU: sharesByGPU,
+				Pool: Pool{
+					URL:         summary.ActivePool.URL,
+					User:        summary.ActivePool.User,
+					Latency:     milli(summary.ActivePool.Ping),
+					Disconnects: summary.ActivePool.Retries,
+				},
+			},
+		},
+		GPUs: gpus,
+	}, nil
+}
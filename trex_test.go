@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	TREX_SUMMARY = `{
+   "accepted_count":2010,
+   "active_pool":{
+      "difficulty":"4.29 G",
+      "last_submit_ts":1615000000,
+      "ping":45,
+      "proxy":"",
+      "retries":1,
+      "url":"stratum+tcp://eu1.ethermine.org:4444",
+      "user":"0x5a6f1d5b4b0f9c5c2d8f4c3e2a1b0c9d8e7f6a5b.wupse",
+      "worker":"wupse"
+   },
+   "algorithm":"ethash",
+   "api":"3.6",
+   "description":"T-Rex NVIDIA GPU miner",
+   "gpu_total":2,
+   "gpus":[
+      {
+         "device_id":0,
+         "efficiency":"335kH/W",
+         "fan_speed":66,
+         "gpu_id":0,
+         "hashrate":30867141,
+         "hashrate_day":30801234,
+         "hashrate_hour":30850000,
+         "hashrate_minute":30870000,
+         "intensity":22,
+         "name":"GeForce GTX 1070",
+         "power":92,
+         "power_avr":93,
+         "cclock":1708,
+         "mclock":4004,
+         "shares":{"accepted_count":1005, "invalid_count":0, "rejected_count":1, "solved_count":0},
+         "temperature":62,
+         "vendor":"Gigabyte"
+      },
+      {
+         "device_id":1,
+         "efficiency":"330kH/W",
+         "fan_speed":70,
+         "gpu_id":1,
+         "hashrate":30867141,
+         "hashrate_day":30801234,
+         "hashrate_hour":30850000,
+         "hashrate_minute":30870000,
+         "intensity":22,
+         "name":"GeForce GTX 1070",
+         "power":94,
+         "power_avr":94,
+         "shares":{"accepted_count":1005, "invalid_count":1, "rejected_count":1, "solved_count":0},
+         "temperature":65,
+         "vendor":"MSI"
+      }
+   ],
+   "hashrate":61734282,
+   "hashrate_day":61602468,
+   "hashrate_hour":61700000,
+   "hashrate_minute":61740000,
+   "invalid_count":1,
+   "name":"t-rex",
+   "os":"linux",
+   "rejected_count":2,
+   "solved_count":0,
+   "success":1,
+   "time":1615003600,
+   "uptime":3600,
+   "version":"0.20.3"
+}`
+)
+
+func TestTRexCollect(t *testing.T) {
+	server := fixtureServer(map[string]string{"/summary": TREX_SUMMARY})
+	defer server.Close()
+
+	miner := NewTRexClient(server.URL)
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "trex", miner.Name())
+	assert.Equal(t, "0.20.3", metrics.Version)
+	assert.Equal(t, 3600.0, metrics.Uptime)
+	assert.Equal(t, "ethash", metrics.Algorithms[0].Name)
+	assert.Equal(t, 2010.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 2.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Invalid)
+	assert.Equal(t, 61734282.0, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{30867141, 30867141}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, 61740000.0, metrics.Algorithms[0].Rates.Windows["60s"])
+	assert.Equal(t, 61602468.0, metrics.Algorithms[0].Rates.Windows["86400s"])
+	assert.Equal(t, 1.0, metrics.Algorithms[0].SharesByGPU[1].Invalid)
+	assert.Equal(t, "stratum+tcp://eu1.ethermine.org:4444", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, 0.045, *metrics.Algorithms[0].Pool.Latency)
+	assert.Equal(t, 1.0, *metrics.Algorithms[0].Pool.Disconnects)
+	assert.Equal(t, "GeForce GTX 1070", metrics.GPUs[0].Card)
+	assert.Equal(t, 62.0, *metrics.GPUs[0].Temperature)
+	assert.Equal(t, 66.0, *metrics.GPUs[0].FanPercent)
+	assert.Equal(t, 92.0, *metrics.GPUs[0].Power)
+	assert.Equal(t, 93.0, *metrics.GPUs[0].AveragePower)
+	assert.Equal(t, 1708.0, *metrics.GPUs[0].CoreClock)
+	assert.Nil(t, metrics.GPUs[1].CoreClock)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+type XMRigClient struct {
+	api XMRigAPI
+}
+
+type XMRigAPI interface {
+	Summary(ctx context.Context) (*xmrigSummary, error)
+	Backends(ctx context.Context) ([]xmrigBackend, error)
+}
+
+// xmrigRates holds the hashrate averaged over 10s, 60s and 15m. Windows
+// that are not filled yet are null.
+type xmrigRates []*float64
+
+// xmrigErrorLog lists the recent errors of results or of the connection.
+// Entries of the results carry a count, those of the connection do not.
+type xmrigErrorLog []struct {
+	Count    *float64 `json:"count"`
+	LastSeen int64    `json:"last_seen"`
+	Text     string   `json:"text"`
+}
+
+type xmrigSummary struct {
+	Version  string `json:"version"`
+	Uptime   int    `json:"uptime"`
+	Algo     string `json:"algo"`
+	Hashrate struct {
+		Total   xmrigRates   `json:"total"`
+		Highest float64      `json:"highest"`
+		Threads []xmrigRates `json:"threads"`
+	} `json:"hashrate"`
+	Results struct {
+		DiffCurrent float64       `json:"diff_current"`
+		SharesGood  int           `json:"shares_good"`
+		SharesTotal int           `json:"shares_total"`
+		AvgTime     int           `json:"avg_time"`
+		HashesTotal float64       `json:"hashes_total"`
+		ErrorLog    xmrigErrorLog `json:"error_log"`
+	} `json:"results"`
+	Connection struct {
+		Pool     string        `json:"pool"`
+		Uptime   int           `json:"uptime"`
+		Ping     int           `json:"ping"`
+		Failures int           `json:"failures"`
+		ErrorLog xmrigErrorLog `json:"error_log"`
+	} `json:"connection"`
+}
+
+type xmrigBackend struct {
+	Type    string        `json:"type"`
+	Enabled bool          `json:"enabled"`
+	Algo    string        `json:"algo"`
+	Threads []xmrigThread `json:"threads"`
+}
+
+type xmrigThread struct {
+	Hashrate xmrigRates `json:"hashrate"`
+	Health   *struct {
+		Name        string    `json:"name"`
+		Temperature float64   `json:"temperature"`
+		Power       float64   `json:"power"`
+		Clock       float64   `json:"clock"`
+		MemClock    float64   `json:"mem_clock"`
+		FanSpeed    []float64 `json:"fan_speed"`
+	} `json:"health"`
+}
+
+type xmrigAPIClient struct {
+	url   string
+	token string
+}
+
+func (c xmrigAPIClient) Summary(ctx context.Context) (*xmrigSummary, error) {
+	result := xmrigSummary{}
+	if err := getJSON(ctx, c.url+"/1/summary", c.token, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c xmrigAPIClient) Backends(ctx context.Context) ([]xmrigBackend, error) {
+	result := []xmrigBackend{}
+	if err := getJSON(ctx, c.url+"/2/backends", c.token, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func NewXMRigClient(address string, token string) *XMRigClient {
+	return &XMRigClient{xmrigAPIClient{baseURL(address), token}}
+}
+
+func (c *XMRigClient) Name() string {
+	return "xmrig"
+}
+
+func (c *XMRigClient) Collect(ctx context.Context) (*Metrics, error) {
+	summary, err := c.api.Summary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Versions before 5.0 have no backends endpoint, in which case the
+	// thread rates of the summary are all there is.
+	backends, err := c.api.Backends(ctx)
+	if err != nil {
+		if e, ok := err.(*httpError); !ok || e.status != 404 {
+			return nil, err
+		}
+		backends = []xmrigBackend{{Enabled: true}}
+		for _, thread := range summary.Hashrate.Threads {
+			backends[0].Threads = append(backends[0].Threads, xmrigThread{Hashrate: thread})
+		}
+	}
+
+	byGPU := []float64{}
+	gpus := []GPU{}
+	for _, backend := range backends {
+		if !backend.Enabled {
+			continue
+		}
+
+		for _, thread := range backend.Threads {
+			byGPU = append(byGPU, thread.Hashrate.at(0))
+
+			gpu := GPU{}
+			if health := thread.Health; health != nil {
+				gpu.Card = health.Name
+				gpu.Temperature = reading(health.Temperature)
+				gpu.Power = reading(health.Power)
+				gpu.CoreClock = reading(health.Clock)
+				gpu.MemoryClock = reading(health.MemClock)
+				if len(health.FanSpeed) > 0 {
+					gpu.FanPercent = reading(health.FanSpeed[0])
+				}
+			}
+			gpus = append(gpus, gpu)
+		}
+	}
+
+	total := summary.Hashrate.Total
+
+	return &Metrics{
+		Version: summary.Version,
+		Uptime:  float64(summary.Uptime),
+		Algorithms: []Algorithm{
+			{
+				Name: summary.Algo,
+				Shares: Shares{
+					Accepted: float64(summary.Results.SharesGood),
+					Rejected: float64(summary.Results.SharesTotal - summary.Results.SharesGood),
+				},
+				Rates: Rates{
+					Total:   total.at(0),
+					ByGPU:   byGPU,
+					Windows: total.windows(),
+				},
+				Pool: Pool{
+					URL:        summary.Connection.Pool,
+					Difficulty: reading(summary.Results.DiffCurrent),
+					// XMRig reports the ping in milliseconds.
+					Latency:     milli(reading(float64(summary.Connection.Ping))),
+					Disconnects: reading(float64(summary.Connection.Failures)),
+					Connected:   reading(float64(summary.Connection.Uptime)),
+					Errors:      errorCounts(summary.Results.ErrorLog, summary.Connection.ErrorLog),
+				},
+			},
+		},
+		GPUs: gpus,
+	}, nil
+}
+
+func (r xmrigRates) at(i int) float64 {
+	if i >= len(r) || r[i] == nil {
+		return 0
+	}
+	return *r[i]
+}
+
+func (r xmrigRates) windows() map[string]float64 {
+	windows := map[string]float64{}
+	for i, name := range []string{"10s", "60s", "900s"} {
+		if i < len(r) && r[i] != nil {
+			windows[name] = *r[i]
+		}
+	}
+	return windows
+}
+
+// errorTypes map phrases of logged errors to a fixed set of types, as the
+// messages themselves contain job ids, addresses and the like. The first
+// matching phrase wins.
+var errorTypes = []struct {
+	phrase string
+	kind   string
+}{
+	{"low difficulty", "low_difficulty"},
+	{"duplicate", "duplicate"},
+	{"stale", "stale"},
+	{"job not found", "stale"},
+	{"expired", "stale"},
+	{"timeout", "timeout"},
+	{"timed out", "timeout"},
+	{"unauthenticated", "auth"},
+	{"unauthorized", "auth"},
+	{"login", "auth"},
+	{"connect", "connection"},
+	{"reset by peer", "connection"},
+	{"broken pipe", "connection"},
+	{"eof", "connection"},
+	{"invalid", "invalid"},
+}
+
+// errorType classifies a logged error message, "other" if nothing matches.
+func errorType(text string) string {
+	text = strings.ToLower(text)
+	for _, t := range errorTypes {
+		if strings.Contains(text, t.phrase) {
+			return t.kind
+		}
+	}
+	return "other"
+}
+
+// errorCounts sums up the error logs by type.
+func errorCounts(logs ...xmrigErrorLog) map[string]float64 {
+	counts := map[string]float64{}
+	for _, entries := range logs {
+		for _, entry := range entries {
+			count := 1.0
+			if entry.Count != nil {
+				count = *entry.Count
+			}
+			counts[errorType(entry.Text)] += count
+		}
+	}
+	return counts
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	XMRIG_SUMMARY = `{
+   "id":"92f3104f9a2ee78c",
+   "worker_id":"wupse",
+   "uptime":3600,
+   "restricted":true,
+   "version":"6.16.2",
+   "kind":"miner",
+   "algo":"rx/0",
+   "hugepages":true,
+   "paused":false,
+   "results":{
+      "diff_current":120001,
+      "shares_good":152,
+      "shares_total":153,
+      "avg_time":23,
+      "hashes_total":25246208901,
+      "best":[1844674407, 935720113],
+      "error_log":[]
+   },
+   "connection":{
+      "pool":"pool.supportxmr.com:443",
+      "ip":"104.243.43.115",
+      "uptime":3590,
+      "ping":45,
+      "failures":1,
+      "tls":"TLSv1.3",
+      "algo":"rx/0",
+      "diff":120001,
+      "accepted":152,
+      "rejected":1,
+      "error_log":[{"last_seen":1615000000, "text":"connection reset by peer"}]
+   },
+   "hashrate":{
+      "total":[7012.3, 7005.1, null],
+      "highest":7100.2,
+      "threads":[[3506.1, 3502.5, null], [3506.2, 3502.6, null]]
+   }
+}`
+
+	XMRIG_BACKENDS = `[
+   {
+      "type":"cpu",
+      "enabled":true,
+      "algo":"rx/0",
+      "profile":"rx",
+      "hw-aes":true,
+      "hashrate":[7012.3, 7005.1, null],
+      "threads":[
+         {"intensity":1, "affinity":0, "av":1, "hashrate":[3506.1, 3502.5, null]},
+         {"intensity":1, "affinity":2, "av":1, "hashrate":[3506.2, 3502.6, null]}
+      ]
+   },
+   {
+      "type":"opencl",
+      "enabled":false,
+      "algo":null,
+      "hashrate":[0, 0, 0],
+      "threads":[]
+   },
+   {
+      "type":"cuda",
+      "enabled":true,
+      "algo":"rx/0",
+      "hashrate":[1612.5, 1610.0, null],
+      "threads":[
+         {
+            "index":0,
+            "threads":32,
+            "blocks":30,
+            "hashrate":[1612.5, 1610.0, null],
+            "health":{"name":"GeForce GTX 1070", "clock":1708, "mem_clock":4004, "power":92, "temperature":63, "fan_speed":[48]}
+         }
+      ]
+   }
+]`
+)
+
+func xmrigServer(backends bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/1/summary":
+			w.Write([]byte(XMRIG_SUMMARY))
+		case r.URL.Path == "/2/backends" && backends:
+			w.Write([]byte(XMRIG_BACKENDS))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestXMRigCollect(t *testing.T) {
+	server := xmrigServer(true)
+	defer server.Close()
+
+	miner := NewXMRigClient(server.URL, "secret")
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "xmrig", miner.Name())
+	assert.Equal(t, "6.16.2", metrics.Version)
+	assert.Equal(t, 3600.0, metrics.Uptime)
+	assert.Equal(t, "rx/0", metrics.Algorithms[0].Name)
+	assert.Equal(t, 152.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 1.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 7012.3, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, map[string]float64{"10s": 7012.3, "60s": 7005.1}, metrics.Algorithms[0].Rates.Windows)
+	assert.Equal(t, []float64{3506.1, 3506.2, 1612.5}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, "pool.supportxmr.com:443", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, 0.045, *metrics.Algorithms[0].Pool.Latency)
+	assert.Equal(t, 1.0, *metrics.Algorithms[0].Pool.Disconnects)
+	assert.Equal(t, map[string]float64{"connection": 1}, metrics.Algorithms[0].Pool.Errors)
+	assert.Equal(t, 3, len(metrics.GPUs))
+	assert.Nil(t, metrics.GPUs[0].Temperature)
+	assert.Equal(t, "GeForce GTX 1070", metrics.GPUs[2].Card)
+	assert.Equal(t, 63.0, *metrics.GPUs[2].Temperature)
+	assert.Equal(t, 48.0, *metrics.GPUs[2].FanPercent)
+}
+
+func TestXMRigCollectWithoutBackends(t *testing.T) {
+	server := xmrigServer(false)
+	defer server.Close()
+
+	metrics, err := NewXMRigClient(server.URL, "secret").Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{3506.1, 3506.2}, metrics.Algorithms[0].Rates.ByGPU)
+}
+
+func TestXMRigCollectUnauthorized(t *testing.T) {
+	server := xmrigServer(true)
+	defer server.Close()
+
+	_, err := NewXMRigClient(server.URL, "wrong").Collect(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "auth", errorReason(context.Background(), err))
+}
+
+func TestErrorType(t *testing.T) {
+	assert.Equal(t, "low_difficulty", errorType("Low difficulty share"))
+	assert.Equal(t, "stale", errorType("Job not found (id 4f2a91)"))
+	assert.Equal(t, "connection", errorType("[pool.supportxmr.com:443] connect error: \"connection refused\""))
+	assert.Equal(t, "auth", errorType("Unauthenticated"))
+	assert.Equal(t, "invalid", errorType("Invalid nonce; is miner not compatible with NiceHash?"))
+	assert.Equal(t, "other", errorType("IP address is banned"))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+// xmrstakReport is the /api.json of xmr-stak, which XMRig's summary was
+// originally modelled on.
+type xmrstakReport struct {
+	Version  string `json:"version"`
+	Hashrate struct {
+		Threads []xmrigRates `json:"threads"`
+		Total   xmrigRates   `json:"total"`
+		Highest float64      `json:"highest"`
+	} `json:"hashrate"`
+	Results struct {
+		DiffCurrent float64       `json:"diff_current"`
+		SharesGood  float64       `json:"shares_good"`
+		SharesTotal float64       `json:"shares_total"`
+		AvgTime     float64       `json:"avg_time"`
+		ErrorLog    xmrigErrorLog `json:"error_log"`
+	} `json:"results"`
+	Connection struct {
+		Pool     string        `json:"pool"`
+		Uptime   float64       `json:"uptime"`
+		Ping     float64       `json:"ping"`
+		ErrorLog xmrigErrorLog `json:"error_log"`
+	} `json:"connection"`
+}
+
+// NewXMRStakClient reads the /api.json of xmr-stak. It reports neither the
+// algorithm nor its own uptime, so the algorithm is the one given.
+func NewXMRStakClient(address string, algorithm string) *HTTPMinerClient {
+	if algorithm == "" {
+		algorithm = "cryptonight"
+	}
+
+	return newHTTPMinerClient(address, "xmrstak", func(ctx context.Context, api HTTPMinerAPI) (*Metrics, error) {
+		return collectXMRStak(ctx, api, algorithm)
+	})
+}
+
+func collectXMRStak(ctx context.Context, api HTTPMinerAPI, algorithm string) (*Metrics, error) {
+	report := xmrstakReport{}
+	if err := api.Get(ctx, "/api.json", &report); err != nil {
+		return nil, err
+	}
+
+	byGPU := []float64{}
+	for _, thread := range report.Hashrate.Threads {
+		byGPU = append(byGPU, thread.at(0))
+	}
+
+	// The version comes as "xmr-stak/2.10.8/69eb3b7/master/lin/nvidia-amd-cpu/0".
+	version := report.Version
+	if parts := strings.Split(version, "/"); len(parts) > 1 {
+		version = parts[1]
+	}
+
+	return &Metrics{
+		Version: version,
+		Algorithms: []Algorithm{
+			{
+				Name: algorithm,
+				Shares: Shares{
+					Accepted: report.Results.SharesGood,
+					Rejected: report.Results.SharesTotal - report.Results.SharesGood,
+				},
+				Rates: Rates{
+					Total:   report.Hashrate.Total.at(0),
+					ByGPU:   byGPU,
+					Windows: report.Hashrate.Total.windows(),
+				},
+				Pool: Pool{
+					URL:        report.Connection.Pool,
+					Difficulty: reading(report.Results.DiffCurrent),
+					Latency:    milli(reading(report.Connection.Ping)),
+					Connected:  reading(report.Connection.Uptime),
+					Errors:     errorCounts(report.Results.ErrorLog, report.Connection.ErrorLog),
+				},
+			},
+		},
+	}, nil
+}
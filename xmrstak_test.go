@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	XMRSTAK_REPORT = `{
+   "version":"xmr-stak/2.10.8/69eb3b7/master/lin/nvidia-amd-cpu/0",
+   "hashrate":{
+      "threads":[[412.3, 410.1, 409.8], [415.0, 411.9, null]],
+      "total":[827.3, 822.0, null],
+      "highest":840.2
+   },
+   "results":{
+      "diff_current":25001,
+      "shares_good":96,
+      "shares_total":99,
+      "avg_time":37.5,
+      "hashes_total":2417850,
+      "best":[8734, 7012, 5432, 4321, 3210, 2109, 1098, 987, 876, 765],
+      "error_log":[
+         {"count":2, "last_seen":1615003400, "text":"Low difficulty share"},
+         {"count":1, "last_seen":1615003500, "text":"Duplicate share"}
+      ]
+   },
+   "connection":{
+      "pool":"pool.supportxmr.com:3333",
+      "uptime":3590,
+      "ping":52,
+      "error_log":[
+         {"last_seen":1615000010, "text":"Low difficulty share"}
+      ]
+   }
+}`
+)
+
+func TestXMRStakCollect(t *testing.T) {
+	server := fixtureServer(map[string]string{"/api.json": XMRSTAK_REPORT})
+	defer server.Close()
+
+	miner := NewXMRStakClient(server.URL, "")
+	metrics, err := miner.Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "xmrstak", miner.Name())
+	assert.Equal(t, "2.10.8", metrics.Version)
+	assert.Equal(t, "cryptonight", metrics.Algorithms[0].Name)
+	assert.Equal(t, 96.0, metrics.Algorithms[0].Shares.Accepted)
+	assert.Equal(t, 3.0, metrics.Algorithms[0].Shares.Rejected)
+	assert.Equal(t, 827.3, metrics.Algorithms[0].Rates.Total)
+	assert.Equal(t, []float64{412.3, 415.0}, metrics.Algorithms[0].Rates.ByGPU)
+	assert.Equal(t, map[string]float64{"10s": 827.3, "60s": 822.0}, metrics.Algorithms[0].Rates.Windows)
+	assert.Equal(t, "pool.supportxmr.com:3333", metrics.Algorithms[0].Pool.URL)
+	assert.Equal(t, 25001.0, *metrics.Algorithms[0].Pool.Difficulty)
+	assert.Equal(t, 0.052, *metrics.Algorithms[0].Pool.Latency)
+	assert.Equal(t, 3590.0, *metrics.Algorithms[0].Pool.Connected)
+	assert.Equal(t, map[string]float64{"low_difficulty": 3, "duplicate": 1}, metrics.Algorithms[0].Pool.Errors)
+}
+
+func TestXMRStakCollectAlgorithm(t *testing.T) {
+	server := fixtureServer(map[string]string{"/api.json": XMRSTAK_REPORT})
+	defer server.Close()
+
+	metrics, err := NewXMRStakClient(server.URL, "cryptonight_v8").Collect(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "cryptonight_v8", metrics.Algorithms[0].Name)
+}